@@ -125,8 +125,7 @@ func TestSpecTutorial(T *testing.T) {
 							})
 						})
 					})
-					s.Describe("can be a slice", func() {
-					})
+					s.It("can be a slice")
 				})
 			})
 		})
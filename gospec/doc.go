@@ -30,6 +30,9 @@ Gospec finds all `*_spec.go` files rooted at `spec/` runs them with Gotest
 Gospec interacts with the "spec" package by setting the GOSPECPATTERN in the
 environment of the spawned Gotest process. This regular expression can select
 which Specs to execute by matching against their context (test) name.
+Similarly, GOSPECSKIP is a regular expression matching Specs not to execute,
+and GOSPECLABELS is a label expression like "integration && !slow" selecting
+Specs by the labels of their blocks.
 
 Additionally, the standard Gotest method of selecting tests by matching their
 function name works. This supercedes Spec selection.
@@ -50,8 +53,12 @@ arguments are joined with a "|" and the value replaces the value of the flag
 
 Options:
 
+    -labels=""      Label expression selecting Specs (GOSPECLABELS).
+
     -root="./spec"  Directory containing spec files.
 
+    -skip=""        Regexp matching Spec contexts not to run (GOSPECSKIP).
+
     -spec=".*"      Regexp matching Spec contexts.
 
     -test=".*"      Regexp matching test names (gotest -test.run).
@@ -45,5 +45,5 @@ func main() {
 		cmd = cmd.Verbose()
 	}
 	cmd = cmd.TestPattern(opt.TestPattern)
-	FatalError(cmd.Run(opt.SpecPattern))
+	FatalError(cmd.Run(opt.SpecPattern, opt.SkipPattern, opt.Labels))
 }
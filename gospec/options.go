@@ -19,7 +19,7 @@ import (
 var (
     // Set this variable to customize the help message header.
     // For example, `gospec [options] action [arg2 ...]`.
-    CommandLineHelpUsage = `gospec [-v] [-test=PATTERN] [ROOT [PATTERN ...]]`
+    CommandLineHelpUsage = `gospec [-v] [-test=PATTERN] [-skip=PATTERN] [-labels=EXPR] [ROOT [PATTERN ...]]`
     // Set this variable to print a message after the option specifications.
     // For example, "For more help:\n\tgospec help [action]"
     CommandLineHelpFooter = `Spec files must end with a suffix "_spec.go".`
@@ -30,6 +30,8 @@ type options struct {
     Root        string
     TestPattern string
     SpecPattern string
+    SkipPattern string
+    Labels      string
     Verbose     bool
 }
 
@@ -40,6 +42,8 @@ func setupFlags(opt *options) *flag.FlagSet {
     fs.StringVar(&(opt.Root), "root", "./spec", "Directory containing spec files.")
     fs.StringVar(&(opt.TestPattern), "test", ".*", "Regexp matching tests to run.")
     fs.StringVar(&(opt.SpecPattern), "spec", ".*", "Regexp matching tests to run.")
+    fs.StringVar(&(opt.SkipPattern), "skip", "", "Regexp matching Specs not to run.")
+    fs.StringVar(&(opt.Labels), "labels", "", "Label expression selecting Specs to run (e.g. \"db && !slow\").")
     setupUsage(fs)
     return fs
 }
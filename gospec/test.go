@@ -36,11 +36,20 @@ func (cmd1 GoTest) TestPattern(pattern string) (cmd2 GoTest) {
 	return
 }
 
-func (cmd GoTest) Run(specpattern string) error {
+//  Run gotest. The "spec" package selects Specs with the patterns and label
+//  expression, which are passed through the environment. An empty skip
+//  pattern or label expression leaves the environment unchanged.
+func (cmd GoTest) Run(specpattern, skippattern, labels string) error {
 	excmd := exec.Command("gotest", cmd...)
 
 	excmd.Env = os.Environ()
 	excmd.Env = append(excmd.Env, fmt.Sprintf("GOSPECPATTERN=%s", specpattern))
+	if skippattern != "" {
+		excmd.Env = append(excmd.Env, fmt.Sprintf("GOSPECSKIP=%s", skippattern))
+	}
+	if labels != "" {
+		excmd.Env = append(excmd.Env, fmt.Sprintf("GOSPECLABELS=%s", labels))
+	}
 
 	excmd.Stdout = os.Stdout
 	excmd.Stderr = os.Stderr
@@ -0,0 +1,133 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    async.go
+ *  Description: Matchers polling function calls until they match.
+ */
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+//  The default timing of Eventually and Consistently.
+var (
+	EventuallyTimeout    = time.Second
+	ConsistentlyDuration = 100 * time.Millisecond
+	PollingInterval      = 10 * time.Millisecond
+)
+
+//  Create a Matcher that calls a nil-adic function object again and again
+//  until its value matches m. It fails if the value doesn't match before a
+//  timeout. The optional timing is a timeout and then a polling interval,
+//  which default to EventuallyTimeout and PollingInterval.
+//      s.Spec(func() int { return count.Load() }, Should, Eventually(Equal), 5)
+//      s.Spec(ready, Should, Eventually(Equal, 2*time.Second), true)
+func Eventually(m Matcher, timing ...time.Duration) Matcher {
+	return newPoll("Eventually", m, true, EventuallyTimeout, timing)
+}
+
+//  Create a Matcher that calls a nil-adic function object again and again,
+//  and fails as soon as its value doesn't match m. It passes when the value
+//  matched for the whole duration. The optional timing is a duration and
+//  then a polling interval, which default to ConsistentlyDuration and
+//  PollingInterval.
+//      s.Spec(func() int { return len(queue) }, Should, Consistently(BeLessThan), 10)
+func Consistently(m Matcher, timing ...time.Duration) Matcher {
+	return newPoll("Consistently", m, false, ConsistentlyDuration, timing)
+}
+
+type poll struct {
+	name       string
+	m          Matcher
+	eventually bool          // Stop at the first match (or mismatch otherwise)
+	timeout    time.Duration // How long to poll
+	interval   time.Duration // The time between polls
+	err        error         // An error from the last poll
+	polls      int           // The number of polls run by Matches
+	last       []interface{} // The arguments of the last poll
+}
+
+func newPoll(name string, m Matcher, eventually bool, timeout time.Duration, timing []time.Duration) *poll {
+	p := &poll{name: name, m: m, eventually: eventually, timeout: timeout, interval: PollingInterval}
+	if len(timing) > 0 {
+		p.timeout = timing[0]
+	}
+	if len(timing) > 1 {
+		p.interval = timing[1]
+	}
+	return p
+}
+
+func (p *poll) Matches(args []interface{}) (pass bool, err error) {
+	p.err, p.polls, p.last = nil, 0, nil
+	if !acceptsNumIn(p.m, len(args)) {
+		return false, errors.New("wrong number of arguments")
+	}
+	fn, ok := args[0].(FnCall)
+	if !ok {
+		return false, fmt.Errorf("%s needs a function call Value, not %T", p.name, args[0])
+	}
+	deadline := time.Now().Add(p.timeout)
+	for {
+		p.polls++
+		p.last = append([]interface{}{fn}, args[1:]...)
+		pass, p.err = p.m.Matches(p.last)
+		if p.err == nil {
+			p.err = p.m.Error()
+		}
+		switch {
+		case p.eventually && pass && p.err == nil:
+			return true, nil
+		case !p.eventually && p.err != nil:
+			return false, nil
+		case !p.eventually && !pass:
+			return false, nil
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+		time.Sleep(p.interval)
+		fn = fn.call()
+	}
+	return !p.eventually, nil
+}
+
+func (p *poll) String() string { return fmt.Sprintf("%s(%s)", p.name, p.m) }
+func (p *poll) Error() error   { return p.err }
+func (p *poll) clone() Matcher {
+	return &poll{name: p.name, m: cloneMatcher(p.m), eventually: p.eventually, timeout: p.timeout, interval: p.interval}
+}
+func (p *poll) NumIn() int { return p.m.NumIn() }
+func (p *poll) IsVariadic() bool {
+	v, ok := p.m.(VariadicMatcher)
+	return ok && v.IsVariadic()
+}
+
+//  Show the last value polled and the explanation of its mismatch.
+func (p *poll) explain(args []interface{}) string {
+	if p.last == nil {
+		return ""
+	}
+	fn := p.last[0].(FnCall)
+	var last string
+	switch {
+	case fn.panicv != nil:
+		last = fmt.Sprintf("panic %#v", fn.panicv)
+	case len(fn.out) == 0:
+		last = "no value"
+	default:
+		last = fmt.Sprintf("%#v", fn.out[0].Interface())
+	}
+	s := fmt.Sprintf("last value %s after %d polls", last, p.polls)
+	if e, ok := p.m.(explainer); ok {
+		if why := e.explain(p.last); why != "" {
+			s += "\n" + why
+		}
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    async_test.go
+ *  Description: For testing async.go
+ */
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventually(T *testing.T) {
+	var count int32
+	go func() {
+		for i := 0; i < 5; i++ {
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&count, 1)
+		}
+	}()
+	load := func() int32 { return atomic.LoadInt32(&count) }
+	describePassing(T, "Eventually", func(s *SpecTest) {
+		s.It("polls until the value matches", func() {
+			s.Spec(load, Should, Eventually(Equal), int32(5))
+			s.Spec(load, Should, Consistently(Equal, 20*time.Millisecond, time.Millisecond), int32(5))
+			s.Spec(load, Should, Eventually(BeBetween), 4, 6)
+		})
+	})
+}
+
+func TestPollFailures(T *testing.T) {
+	var n int
+	poll := func() int { n++; return n }
+	root := runDescribed("Polling matchers", func(s *SpecTest) {
+		s.It("report the last value", func() {
+			s.Spec(func() int { return 1 }, Should, Eventually(Equal, 20*time.Millisecond, 5*time.Millisecond), 2)
+			s.Spec(poll, Should, Consistently(BeLessThan, time.Second, time.Millisecond), 3)
+		})
+		s.It("report errors", func() {
+			s.Spec(1, Should, Eventually(Equal), 1)
+			s.Spec(func() int { panic("boom") }, Should, Consistently(Equal), 1)
+		})
+	})
+	expectResults(T, root.Children[0], Failed,
+		"last value 1 after ...",
+		"last value 3 after 3 polls\n3 == 3")
+	expectResults(T, root.Children[1], Errored,
+		"Eventually needs a function call Value, not int",
+		"function call panicked: boom\ngoroutine...")
+	if r := root.Children[0].Results; len(r) > 1 && !strings.Contains(r[1].String(), "Should Consistently(BeLessThan) 3") {
+		T.Errorf("unexpected result: %s", r[1])
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    channel.go
+ *  Description: Matchers sending and receiving on channels.
+ */
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+//  Matchers for channels of any type.
+//      s.Spec(ch, Should, Receive)
+//      s.Spec(ch, Should, Receive, time.Second, &v)
+//      s.Spec(ch, Should, Receive, Equal, 3)
+//      s.Spec(done, Should, BeClosed)
+//      s.Spec(ch, Should, BeSent, 3, time.Second)
+//  Receive passes when a value is received. Its optional arguments are a
+//  time.Duration to wait for the value, a pointer that the value is stored
+//  in, and a Matcher (with its arguments) that the value must match. A
+//  closed channel doesn't Receive. BeClosed passes when the channel is
+//  closed; it receives a value if one is ready. BeSent passes when the
+//  value can be sent, waiting as long as an optional time.Duration. Without
+//  a time.Duration, these matchers don't wait.
+var (
+	Receive  Matcher = new(receive)
+	BeClosed         = explained(MatcherMust(NewMatcher("BeClosed", matcherBeClosed)), explainClosed)
+	BeSent           = explained(MatcherMust(NewMatcher("BeSent", matcherBeSent)), explainSent)
+)
+
+//  The reflect.Value of a channel Spec value that can be used in direction
+//  dir.
+func chanOf(name string, x interface{}, dir reflect.ChanDir) (ch reflect.Value, err error) {
+	if fn, ok := x.(FnCall); ok && (fn.panicv != nil || len(fn.out) == 0) {
+		return ch, fnerror{fn}
+	}
+	ch = reflect.ValueOf(valueOfSpecValue(x))
+	if ch.Kind() != reflect.Chan {
+		return ch, fmt.Errorf("%s needs a channel, not %T", name, valueOfSpecValue(x))
+	}
+	if ch.Type().ChanDir()&dir == 0 {
+		return ch, fmt.Errorf("%s can't use a %s", name, ch.Type())
+	}
+	return
+}
+
+//  The optional time.Duration argument of a channel matcher.
+func timeoutOf(name string, args []interface{}) (timeout time.Duration, rest []interface{}, err error) {
+	if len(args) == 0 {
+		return 0, args, nil
+	}
+	if timeout, ok := args[0].(time.Duration); ok {
+		return timeout, args[1:], nil
+	}
+	return 0, args, fmt.Errorf("%s needs a time.Duration, not %T", name, args[0])
+}
+
+//  Choose between cases of a select, giving up after timeout.
+func selectTimeout(c reflect.SelectCase, timeout time.Duration) (done bool, v reflect.Value, ok bool) {
+	cases := []reflect.SelectCase{c, {Dir: reflect.SelectDefault}}
+	if timeout > 0 {
+		cases[1] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(time.After(timeout))}
+	}
+	chosen, v, ok := reflect.Select(cases)
+	return chosen == 0, v, ok && chosen == 0
+}
+
+type receive struct {
+	err      error
+	timeout  time.Duration // How long the last Matches waited
+	received bool          // A value was received (or the channel was closed)
+	closed   bool          // The channel was closed
+	got      reflect.Value // The value received
+	m        Matcher       // Matches the received value
+	margs    []interface{} // The arguments of the last call of m
+}
+
+func (r *receive) Matches(args []interface{}) (pass bool, err error) {
+	*r = receive{}
+	if len(args) < 1 {
+		return false, errors.New("wrong number of arguments")
+	}
+	ch, err := chanOf("Receive", args[0], reflect.RecvDir)
+	if err != nil {
+		r.err = err
+		return false, nil
+	}
+	args = args[1:]
+	if len(args) > 0 {
+		if d, ok := args[0].(time.Duration); ok {
+			r.timeout, args = d, args[1:]
+		}
+	}
+	var capture reflect.Value
+	if len(args) > 0 {
+		if _, ok := args[0].(Matcher); !ok && reflect.ValueOf(args[0]).Kind() == reflect.Ptr {
+			v := reflect.ValueOf(args[0])
+			if !ch.Type().Elem().AssignableTo(v.Type().Elem()) || v.IsNil() {
+				r.err = fmt.Errorf("Receive can't store a %s in a %T", ch.Type().Elem(), args[0])
+				return false, nil
+			}
+			capture, args = v, args[1:]
+		}
+	}
+	if len(args) > 0 {
+		m, ok := args[0].(Matcher)
+		if !ok {
+			r.err = fmt.Errorf("Receive needs a time.Duration, a pointer, or a Matcher, not %T", args[0])
+			return false, nil
+		}
+		if !acceptsNumIn(m, len(args)) {
+			r.err = fmt.Errorf("Receive can't give %s %d arguments", m, len(args)-1)
+			return false, nil
+		}
+		r.m = m
+	}
+
+	r.received, r.got, pass = selectTimeout(reflect.SelectCase{Dir: reflect.SelectRecv, Chan: ch}, r.timeout)
+	r.closed = r.received && !pass
+	if !pass {
+		return
+	}
+	if capture.IsValid() {
+		capture.Elem().Set(r.got)
+	}
+	if r.m != nil {
+		r.margs = append([]interface{}{r.got.Interface()}, args[1:]...)
+		pass, r.err = r.m.Matches(r.margs)
+		if r.err == nil {
+			r.err = r.m.Error()
+		}
+	}
+	return
+}
+
+func (r *receive) String() string   { return "Receive" }
+func (r *receive) Error() error     { return r.err }
+func (r *receive) clone() Matcher   { return new(receive) }
+func (r *receive) NumIn() int       { return 1 }
+func (r *receive) IsVariadic() bool { return true }
+
+//  Explain what the last call of Matches received.
+func (r *receive) explain(args []interface{}) string {
+	switch {
+	case r.closed:
+		return "channel is closed"
+	case !r.received && r.timeout > 0:
+		return fmt.Sprintf("nothing received within %v", r.timeout)
+	case !r.received:
+		return "nothing received"
+	}
+	s := fmt.Sprintf("received %s", formatValue(r.got))
+	if e, ok := r.m.(explainer); ok {
+		if why := e.explain(r.margs); why != "" {
+			s += "\n" + why
+		}
+	}
+	return s
+}
+
+func matcherBeClosed(x interface{}) (pass bool, err error) {
+	ch, err := chanOf("BeClosed", x, reflect.RecvDir)
+	if err != nil {
+		return
+	}
+	done, _, ok := selectTimeout(reflect.SelectCase{Dir: reflect.SelectRecv, Chan: ch}, 0)
+	return done && !ok, nil
+}
+
+func explainClosed(args []interface{}) string {
+	return "channel is open"
+}
+
+func matcherBeSent(x, value interface{}, timeout ...interface{}) (pass bool, err error) {
+	ch, err := chanOf("BeSent", x, reflect.SendDir)
+	if err != nil {
+		return
+	}
+	wait, rest, err := timeoutOf("BeSent", timeout)
+	if err != nil {
+		return
+	} else if len(rest) > 0 {
+		return false, fmt.Errorf("Unexpected arguments %v", rest)
+	}
+	v := reflect.ValueOf(value)
+	switch elem := ch.Type().Elem(); {
+	case !v.IsValid() && canBeNil(elem.Kind()):
+		v = reflect.Zero(elem)
+	case !v.IsValid() || !v.Type().AssignableTo(elem):
+		return false, fmt.Errorf("BeSent can't send %#v on a %s", value, ch.Type())
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			pass, err = false, fmt.Errorf("BeSent %v", e)
+		}
+	}()
+	pass, _, _ = selectTimeout(reflect.SelectCase{Dir: reflect.SelectSend, Chan: ch, Send: v}, wait)
+	return
+}
+
+func explainSent(args []interface{}) string {
+	if len(args) > 2 {
+		return fmt.Sprintf("channel not ready to receive within %v", args[2])
+	}
+	return "channel not ready to receive"
+}
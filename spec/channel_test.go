@@ -0,0 +1,83 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    channel_test.go
+ *  Description: For testing channel.go
+ */
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChannelMatchers(T *testing.T) {
+	ch := make(chan int, 1)
+	done := make(chan struct{})
+	var got int
+	describePassing(T, "Channel matchers", func(s *SpecTest) {
+		s.It("receive", func() {
+			s.Spec(ch, Should, Not, Receive)
+			ch <- 1
+			s.Spec(ch, Should, Receive, &got)
+			s.Spec(got, Should, Equal, 1)
+			go func() { ch <- 2 }()
+			s.Spec((<-chan int)(ch), Should, Receive, time.Second, Equal, 2)
+			ch <- 3
+			s.Spec(ch, Should, Receive, BeBetween, 1, 5)
+		})
+		s.It("check for closed channels", func() {
+			s.Spec(done, Should, Not, BeClosed)
+			close(done)
+			s.Spec(done, Should, BeClosed)
+			s.Spec(done, Should, Not, Receive)
+		})
+		s.It("send", func() {
+			s.Spec(ch, Should, BeSent, 4)
+			s.Spec(ch, Should, Not, BeSent, 5, time.Millisecond)
+			s.Spec(ch, Should, Receive, Equal, 4)
+			s.Spec(make(chan error, 1), Should, BeSent, nil)
+		})
+	})
+}
+
+func TestChannelFailures(T *testing.T) {
+	ch := make(chan int, 1)
+	closed := make(chan int)
+	close(closed)
+	root := runDescribed("Channel matchers", func(s *SpecTest) {
+		s.It("explain failures", func() {
+			s.Spec(ch, Should, Receive, time.Millisecond)
+			s.Spec(closed, Should, Receive)
+			ch <- 1
+			s.Spec(ch, Should, Receive, Equal, 2)
+			s.Spec(ch, Should, BeClosed)
+			s.Spec(make(chan int), Should, BeSent, 1)
+		})
+		s.It("report errors", func() {
+			s.Spec(1, Should, Receive)
+			s.Spec(make(chan<- int), Should, Receive)
+			s.Spec(ch, Should, Receive, new(string))
+			s.Spec(ch, Should, Receive, 1)
+			s.Spec(ch, Should, BeSent, "a")
+			s.Spec(closed, Should, BeSent, 1)
+			s.Spec(ch, Should, BeSent, 1, 5)
+		})
+	})
+	expectResults(T, root.Children[0], Failed,
+		"nothing received within 1ms",
+		"channel is closed",
+		"received 1\n1 != 2",
+		"channel is open",
+		"channel not ready to receive")
+	expectResults(T, root.Children[1], Errored,
+		"Receive needs a channel, not int",
+		"Receive can't use a chan<- int",
+		"Receive can't store a int in a *string",
+		"Receive needs a time.Duration, a pointer, or a Matcher, not int",
+		"BeSent can't send \"a\" on a chan int",
+		"BeSent send on closed channel",
+		"BeSent needs a time.Duration, not int")
+}
@@ -0,0 +1,326 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    collection.go
+ *  Description: Matchers for slices, arrays, maps, strings and channels.
+ */
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//  Matchers for collections (slices, arrays, maps, strings, and channels).
+//  The elements of a map are its values. Channels only support HaveLen,
+//  HaveCap, and BeEmpty, which inspect their buffers.
+//      s.Spec([]int{1, 2}, Should, Contain, 2)
+//      s.Spec("abc", Should, Contain, "bc")
+//      s.Spec([]int{1, 2, 3}, Should, ContainElements, []int{3, 1})
+//      s.Spec([]int{1, 2, 3}, Should, ConsistOf, []int{3, 1, 2})
+//      s.Spec(m, Should, HaveLen, 3)
+//      s.Spec(make(chan int, 4), Should, HaveCap, 4)
+//      s.Spec(m, Should, Not, BeEmpty)
+//      s.Spec(m, Should, HaveKey, "a")
+//      s.Spec(m, Should, HaveKeyWithValue, "a", 1)
+var (
+	Contain          = explained(MatcherMust(NewMatcher("Contain", matcherContain)), explainContain)
+	ContainElements  = explained(MatcherMust(NewMatcher("ContainElements", matcherContainElements)), explainContainElements)
+	ConsistOf        = explained(MatcherMust(NewMatcher("ConsistOf", matcherConsistOf)), explainConsistOf)
+	HaveLen          = explained(MatcherMust(NewMatcher("HaveLen", matcherHaveLen)), explainLen)
+	HaveCap          = explained(MatcherMust(NewMatcher("HaveCap", matcherHaveCap)), explainCap)
+	BeEmpty          = explained(MatcherMust(NewMatcher("BeEmpty", matcherBeEmpty)), explainLen)
+	HaveKey          = explained(MatcherMust(NewMatcher("HaveKey", matcherHaveKey)), explainKeys)
+	HaveKeyWithValue = explained(MatcherMust(NewMatcher("HaveKeyWithValue", matcherHaveKeyWithValue)), explainKeyWithValue)
+)
+
+//  The kinds of values the collection matchers accept.
+func isCollection(k reflect.Kind) bool {
+	switch k {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String, reflect.Chan:
+		return true
+	}
+	return false
+}
+
+//  The reflect.Value of a collection Spec value.
+func collectionOf(name string, x interface{}) (v reflect.Value, err error) {
+	v = reflect.ValueOf(valueOfSpecValue(x))
+	if !v.IsValid() || !isCollection(v.Kind()) {
+		err = fmt.Errorf("%s needs a slice, array, map, string or channel, not %T", name, valueOfSpecValue(x))
+	}
+	return
+}
+
+//  The elements of a slice, array or map. Interface elements are unwrapped.
+func elementsOf(name string, v reflect.Value) (elems []reflect.Value, err error) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			elems = append(elems, v.Index(i))
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sortValues(keys)
+		for _, k := range keys {
+			elems = append(elems, v.MapIndex(k))
+		}
+	default:
+		return nil, fmt.Errorf("%s can't get the elements of a %s", name, v.Kind())
+	}
+	for i := range elems {
+		elems[i] = unwrapInterface(elems[i])
+	}
+	return
+}
+
+func unwrapInterface(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		return v.Elem()
+	}
+	return v
+}
+
+//  Returns the index of an element of elems equal to x, or -1.
+func indexOf(elems []reflect.Value, x reflect.Value, skip []bool) int {
+	for i, e := range elems {
+		if (skip == nil || !skip[i]) && elemEqual(e, x) {
+			return i
+		}
+	}
+	return -1
+}
+
+//  Returns true if the element e equals x. An invalid x (nil) equals nil
+//  elements.
+func elemEqual(e, x reflect.Value) bool {
+	if !x.IsValid() {
+		return canBeNil(e.Kind()) && e.IsNil()
+	}
+	return exactly.equal(e, x)
+}
+
+func matcherContain(x, elem interface{}) (pass bool, err error) {
+	v, err := collectionOf("Contain", x)
+	if err != nil {
+		return
+	}
+	elem = valueOfSpecValue(elem)
+	if v.Kind() == reflect.String {
+		sub, ok := elem.(string)
+		if !ok {
+			return false, fmt.Errorf("Contain needs a string to look for in a string, not %T", elem)
+		}
+		return strings.Contains(v.String(), sub), nil
+	}
+	elems, err := elementsOf("Contain", v)
+	if err != nil {
+		return
+	}
+	return indexOf(elems, reflect.ValueOf(elem), nil) >= 0, nil
+}
+
+func explainContain(args []interface{}) string {
+	return fmt.Sprintf("%#v does not contain %#v", valueOfSpecValue(args[0]), valueOfSpecValue(args[1]))
+}
+
+//  Find the elements of expected missing from x. When bag is true each
+//  element of x can only match one expected element.
+func missingElements(name string, x, expected interface{}, bag bool) (elems, missing []reflect.Value, err error) {
+	v, err := collectionOf(name, x)
+	if err != nil {
+		return
+	}
+	if elems, err = elementsOf(name, v); err != nil {
+		return
+	}
+	w := reflect.ValueOf(valueOfSpecValue(expected))
+	if !w.IsValid() || w.Kind() != reflect.Slice && w.Kind() != reflect.Array {
+		err = fmt.Errorf("%s needs a slice of elements, not %T", name, valueOfSpecValue(expected))
+		return
+	}
+	want, _ := elementsOf(name, w)
+	var used []bool
+	if bag {
+		used = make([]bool, len(elems))
+	}
+	for _, e := range want {
+		i := indexOf(elems, e, used)
+		if i < 0 {
+			missing = append(missing, e)
+		} else if bag {
+			used[i] = true
+		}
+	}
+	if bag {
+		var extra []reflect.Value
+		for i, ok := range used {
+			if !ok {
+				extra = append(extra, elems[i])
+			}
+		}
+		elems = extra
+	}
+	return
+}
+
+func formatValues(vals []reflect.Value) string {
+	s := make([]string, len(vals))
+	for i := range vals {
+		s[i] = formatValue(vals[i])
+	}
+	return strings.Join(s, ", ")
+}
+
+func matcherContainElements(x, expected interface{}) (pass bool, err error) {
+	_, missing, err := missingElements("ContainElements", x, expected, false)
+	return err == nil && len(missing) == 0, err
+}
+
+func explainContainElements(args []interface{}) string {
+	_, missing, _ := missingElements("ContainElements", args[0], args[1], false)
+	return fmt.Sprintf("missing elements: %s", formatValues(missing))
+}
+
+func matcherConsistOf(x, expected interface{}) (pass bool, err error) {
+	extra, missing, err := missingElements("ConsistOf", x, expected, true)
+	return err == nil && len(missing) == 0 && len(extra) == 0, err
+}
+
+func explainConsistOf(args []interface{}) string {
+	extra, missing, _ := missingElements("ConsistOf", args[0], args[1], true)
+	var s []string
+	if len(missing) > 0 {
+		s = append(s, fmt.Sprintf("missing elements: %s", formatValues(missing)))
+	}
+	if len(extra) > 0 {
+		s = append(s, fmt.Sprintf("unexpected elements: %s", formatValues(extra)))
+	}
+	return strings.Join(s, "\n")
+}
+
+//  The int value of a length or capacity argument.
+func intArg(name string, n interface{}) (int, error) {
+	v := reflect.ValueOf(valueOfSpecValue(n))
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()), nil
+	}
+	return 0, fmt.Errorf("%s needs an integer, not %T", name, valueOfSpecValue(n))
+}
+
+func matcherHaveLen(x, n interface{}) (pass bool, err error) {
+	v, err := collectionOf("HaveLen", x)
+	if err != nil {
+		return
+	}
+	k, err := intArg("HaveLen", n)
+	return err == nil && v.Len() == k, err
+}
+
+func explainLen(args []interface{}) string {
+	v := reflect.ValueOf(valueOfSpecValue(args[0]))
+	return fmt.Sprintf("%#v has length %d", v, v.Len())
+}
+
+func matcherHaveCap(x, n interface{}) (pass bool, err error) {
+	v, err := collectionOf("HaveCap", x)
+	if err != nil {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.String:
+		return false, fmt.Errorf("HaveCap can't get the capacity of a %s", v.Kind())
+	}
+	k, err := intArg("HaveCap", n)
+	return err == nil && v.Cap() == k, err
+}
+
+func explainCap(args []interface{}) string {
+	v := reflect.ValueOf(valueOfSpecValue(args[0]))
+	return fmt.Sprintf("%#v has capacity %d", v, v.Cap())
+}
+
+func matcherBeEmpty(x interface{}) (pass bool, err error) {
+	v, err := collectionOf("BeEmpty", x)
+	return err == nil && v.Len() == 0, err
+}
+
+//  The value of a map key argument for the map m.
+func keyOf(name string, m reflect.Value, key interface{}) (k reflect.Value, err error) {
+	if m.Kind() != reflect.Map {
+		return k, fmt.Errorf("%s needs a map, not a %s", name, m.Kind())
+	}
+	k = reflect.ValueOf(valueOfSpecValue(key))
+	switch {
+	case !k.IsValid():
+		k = reflect.Zero(m.Type().Key())
+		if !canBeNil(k.Kind()) {
+			err = fmt.Errorf("%s key nil is not a %s", name, m.Type().Key())
+		}
+	case !k.Type().AssignableTo(m.Type().Key()):
+		err = fmt.Errorf("%s key %#v is not a %s", name, k, m.Type().Key())
+	}
+	return
+}
+
+//  Returns true if values of kind k can be nil.
+func canBeNil(k reflect.Kind) bool {
+	switch k {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func matcherHaveKey(x, key interface{}) (pass bool, err error) {
+	m, err := collectionOf("HaveKey", x)
+	if err != nil {
+		return
+	}
+	k, err := keyOf("HaveKey", m, key)
+	if err != nil {
+		return
+	}
+	return m.MapIndex(k).IsValid(), nil
+}
+
+func explainKeys(args []interface{}) string {
+	keys := reflect.ValueOf(valueOfSpecValue(args[0])).MapKeys()
+	sortValues(keys)
+	return fmt.Sprintf("keys are %s", formatValues(keys))
+}
+
+func matcherHaveKeyWithValue(x, key, value interface{}) (pass bool, err error) {
+	m, err := collectionOf("HaveKeyWithValue", x)
+	if err != nil {
+		return
+	}
+	k, err := keyOf("HaveKeyWithValue", m, key)
+	if err != nil {
+		return
+	}
+	v := m.MapIndex(k)
+	if !v.IsValid() {
+		return false, nil
+	}
+	return elemEqual(unwrapInterface(v), reflect.ValueOf(valueOfSpecValue(value))), nil
+}
+
+func explainKeyWithValue(args []interface{}) string {
+	m := reflect.ValueOf(valueOfSpecValue(args[0]))
+	k, err := keyOf("HaveKeyWithValue", m, args[1])
+	if err != nil {
+		return ""
+	}
+	v := m.MapIndex(k)
+	if !v.IsValid() {
+		return explainKeys(args)
+	}
+	return exactly.diffValues(unwrapInterface(v).Interface(), valueOfSpecValue(args[2]))
+}
@@ -0,0 +1,85 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    collection_test.go
+ *  Description: For testing collection.go
+ */
+
+import (
+	"testing"
+)
+
+func TestCollectionMatchers(T *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+	ch := make(chan int, 3)
+	ch <- 1
+	describePassing(T, "Collection matchers", func(s *SpecTest) {
+		s.It("match", func() {
+			s.Spec([]int{1, 2}, Should, Contain, 2)
+			s.Spec([3]string{"x"}, Should, Contain, "x")
+			s.Spec([]interface{}{1, nil}, Should, Contain, nil)
+			s.Spec("abc", Should, Contain, "bc")
+			s.Spec(m, Should, Contain, 2)
+			s.Spec([]int{1, 2, 3}, Should, ContainElements, []int{3, 1})
+			s.Spec([]int{1, 2, 2}, Should, ConsistOf, []int{2, 1, 2})
+			s.Spec([]int{1, 2, 2}, Should, Not, ConsistOf, []int{2, 1, 1})
+			s.Spec(m, Should, ConsistOf, []int{2, 1})
+			s.Spec(m, Should, HaveLen, 2)
+			s.Spec("abc", Should, HaveLen, 3)
+			s.Spec(ch, Should, HaveLen, 1)
+			s.Spec(ch, Should, HaveCap, 3)
+			s.Spec(make([]int, 0, 5), Should, HaveCap, 5)
+			s.Spec(map[int]bool{}, Should, BeEmpty)
+			s.Spec(ch, Should, Not, BeEmpty)
+			s.Spec(m, Should, HaveKey, "a")
+			s.Spec(m, Should, Not, HaveKey, "c")
+			s.Spec(m, Should, HaveKeyWithValue, "b", 2)
+			s.Spec(m, Should, Not, HaveKeyWithValue, "b", 1)
+			s.Spec(func() ([]int, error) { return []int{4}, nil }, Should, Contain, 4)
+		})
+	})
+}
+
+func TestCollectionExplanations(T *testing.T) {
+	for _, test := range []struct {
+		m      Matcher
+		args   []interface{}
+		expect string
+	}{
+		{Contain, []interface{}{[]int{1}, 2}, "[]int{1} does not contain 2"},
+		{ContainElements, []interface{}{[]int{1}, []int{1, 2, 3}}, "missing elements: 2, 3"},
+		{ConsistOf, []interface{}{[]int{1, 4}, []int{1, 2}}, "missing elements: 2\nunexpected elements: 4"},
+		{HaveLen, []interface{}{"ab", 3}, `"ab" has length 2`},
+		{HaveKey, []interface{}{map[string]int{"b": 1, "a": 2}, "c"}, `keys are "a", "b"`},
+		{HaveKeyWithValue, []interface{}{map[string]int{"a": 2}, "a", 1}, "2 != 1"},
+	} {
+		if pass, err := test.m.Matches(test.args); pass || err != nil {
+			T.Errorf("%s matched %v (%v)", test.m, test.args, err)
+		}
+		if s := test.m.(explainer).explain(test.args); s != test.expect {
+			T.Errorf("%s: unexpected explanation %q", test.m, s)
+		}
+	}
+}
+
+func TestCollectionErrors(T *testing.T) {
+	for _, test := range []struct {
+		m    Matcher
+		args []interface{}
+	}{
+		{Contain, []interface{}{1, 1}},
+		{Contain, []interface{}{make(chan int), 1}},
+		{Contain, []interface{}{"abc", 1}},
+		{HaveCap, []interface{}{map[int]int{}, 1}},
+		{HaveKey, []interface{}{[]int{}, 1}},
+		{HaveKey, []interface{}{map[string]int{}, 1}},
+		{HaveLen, []interface{}{"", "a"}},
+	} {
+		if _, err := test.m.Matches(test.args); err == nil && test.m.Error() == nil {
+			T.Errorf("%s accepted %#v", test.m, test.args)
+		}
+	}
+}
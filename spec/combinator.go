@@ -0,0 +1,286 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    combinator.go
+ *  Description: Matchers built from other Matchers.
+ */
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//  Run the Matcher m, explaining the match when it fails.
+func runMatcher(m Matcher, args []interface{}) (pass bool, why string, err error) {
+	m = cloneMatcher(m)
+	pass, err = m.Matches(args)
+	if err == nil {
+		err = m.Error()
+	}
+	if e, ok := m.(explainer); ok && !pass && err == nil {
+		why = e.explain(args)
+	}
+	return
+}
+
+//  Indent an explanation below a line saying which Matcher it is for.
+func nested(m Matcher, why string) string {
+	if why == "" {
+		return fmt.Sprintf("%s failed", m)
+	}
+	return fmt.Sprintf("%s failed\n\t%s", m, strings.Replace(why, "\n", "\n\t", -1))
+}
+
+func matcherNames(ms []Matcher) string {
+	s := make([]string, len(ms))
+	for i := range ms {
+		s[i] = ms[i].String()
+	}
+	return strings.Join(s, ", ")
+}
+
+type combination struct {
+	name string
+	ms   []Matcher
+	all  bool   // Every Matcher must match (AllOf), or any (AnyOf)
+	err  error  // An error from the last call of Matches
+	why  string // The explanation of the last failed match
+}
+
+//  Create a Matcher that matches when all Matchers ms match. Each Matcher
+//  gets the object of the Spec, and the arguments following the combined
+//  Matcher are given to the Matchers in order. Only the last Matcher can be
+//  variadic.
+//      s.Spec(x, Should, AllOf(BeGreaterThan, BeLessThan), 0, 10)
+//  A failure explains which Matcher failed.
+func AllOf(ms ...Matcher) Matcher { return &combination{name: "AllOf", ms: ms, all: true} }
+
+//  Create a Matcher that matches when any of the Matchers ms match. The
+//  arguments are given to ms like AllOf.
+//      s.Spec(err, Should, AnyOf(HaveErrorMatching, HaveErrorMatching), io.EOF, io.ErrUnexpectedEOF)
+//  A failure explains why each Matcher failed.
+func AnyOf(ms ...Matcher) Matcher { return &combination{name: "AnyOf", ms: ms} }
+
+//  AllOf with two Matchers.
+func And(m1, m2 Matcher) Matcher { return AllOf(m1, m2) }
+
+//  AnyOf with two Matchers.
+func Or(m1, m2 Matcher) Matcher { return AnyOf(m1, m2) }
+
+//  Split the arguments of a combination between its Matchers.
+func (c *combination) split(args []interface{}) (parts [][]interface{}, err error) {
+	rest := args[1:]
+	for i, m := range c.ms {
+		n := m.NumIn() - 1
+		if len(rest) < n {
+			return nil, errors.New("Missing argument")
+		}
+		if v, ok := m.(VariadicMatcher); ok && v.IsVariadic() {
+			if i < len(c.ms)-1 {
+				return nil, fmt.Errorf("%s can only have a variadic Matcher last", c.name)
+			}
+			n = len(rest)
+		}
+		parts = append(parts, append([]interface{}{args[0]}, rest[:n]...))
+		rest = rest[n:]
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("Unexpected arguments %v", rest)
+	}
+	return
+}
+
+func (c *combination) Matches(args []interface{}) (pass bool, err error) {
+	c.err, c.why = nil, ""
+	if len(c.ms) == 0 {
+		return false, fmt.Errorf("%s needs a Matcher", c.name)
+	}
+	parts, err := c.split(args)
+	if err != nil {
+		return
+	}
+	var whys []string
+	for i, m := range c.ms {
+		pass, why, err := runMatcher(m, parts[i])
+		if err != nil {
+			c.err = err
+			if !strings.HasPrefix(err.Error(), m.String()) {
+				c.err = fmt.Errorf("%s: %v", m, err)
+			}
+			return false, nil
+		}
+		if pass && !c.all {
+			return true, nil
+		}
+		if !pass {
+			whys = append(whys, nested(m, why))
+			if c.all {
+				break
+			}
+		}
+	}
+	c.why = strings.Join(whys, "\n")
+	return len(whys) == 0, nil
+}
+
+func (c *combination) String() string { return fmt.Sprintf("%s(%s)", c.name, matcherNames(c.ms)) }
+func (c *combination) Error() error   { return c.err }
+func (c *combination) clone() Matcher {
+	ms := make([]Matcher, len(c.ms))
+	for i, m := range c.ms {
+		ms[i] = cloneMatcher(m)
+	}
+	return &combination{name: c.name, ms: ms, all: c.all}
+}
+func (c *combination) NumIn() int {
+	n := 1
+	for _, m := range c.ms {
+		n += m.NumIn() - 1
+	}
+	return n
+}
+func (c *combination) IsVariadic() bool {
+	if len(c.ms) == 0 {
+		return false
+	}
+	v, ok := c.ms[len(c.ms)-1].(VariadicMatcher)
+	return ok && v.IsVariadic()
+}
+func (c *combination) explain(args []interface{}) string { return c.why }
+
+type negation struct {
+	m   Matcher
+	err error
+}
+
+//  Create a Matcher that matches when m doesn't. It works like the Not
+//  sugar, for use inside other combinators.
+//      s.Spec(x, Should, AllOf(Negate(Equal), BeLessThan), 0, 10)
+func Negate(m Matcher) Matcher { return &negation{m: m} }
+
+func (n *negation) Matches(args []interface{}) (pass bool, err error) {
+	pass, _, n.err = runMatcher(n.m, args)
+	return !pass, nil
+}
+
+func (n *negation) String() string { return fmt.Sprintf("Negate(%s)", n.m) }
+func (n *negation) Error() error   { return n.err }
+func (n *negation) clone() Matcher { return &negation{m: cloneMatcher(n.m)} }
+func (n *negation) NumIn() int     { return n.m.NumIn() }
+func (n *negation) IsVariadic() bool {
+	v, ok := n.m.(VariadicMatcher)
+	return ok && v.IsVariadic()
+}
+func (n *negation) explain(args []interface{}) string { return fmt.Sprintf("%s matched", n.m) }
+
+type transform struct {
+	fn  reflect.Value
+	m   Matcher
+	err error
+	why string
+}
+
+//  Create a Matcher that applies m to the result of calling fn on the
+//  object. Function fn must take one argument and return one value.
+//      s.Spec(users, Should, WithTransform(func(u []User) int { return len(u) }, BeGreaterThan), 1)
+func WithTransform(fn interface{}, m Matcher) Matcher {
+	return &transform{fn: reflect.ValueOf(fn), m: m}
+}
+
+//  Call the transformation on x.
+func (t *transform) apply(x interface{}) (y interface{}, err error) {
+	if t.fn.Kind() != reflect.Func || t.fn.Type().NumIn() != 1 || t.fn.Type().NumOut() != 1 {
+		return nil, errors.New("WithTransform needs a function of one argument and one return value")
+	}
+	typ := t.fn.Type()
+	if fn, ok := x.(FnCall); ok && (fn.panicv != nil || len(fn.out) == 0) {
+		return nil, fnerror{fn}
+	}
+	v := reflect.ValueOf(valueOfSpecValue(x))
+	switch {
+	case !v.IsValid() && canBeNil(typ.In(0).Kind()):
+		v = reflect.Zero(typ.In(0))
+	case !v.IsValid() || !v.Type().AssignableTo(typ.In(0)):
+		return nil, fmt.Errorf("WithTransform can't give %#v to a %s", valueOfSpecValue(x), typ)
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			err = recovered(e)
+		}
+	}()
+	return t.fn.Call([]reflect.Value{v})[0].Interface(), nil
+}
+
+func (t *transform) Matches(args []interface{}) (pass bool, err error) {
+	t.err, t.why = nil, ""
+	if len(args) < 1 {
+		return false, errors.New("wrong number of arguments")
+	}
+	y, err := t.apply(args[0])
+	if err != nil {
+		t.err = err
+		return false, nil
+	}
+	margs := append([]interface{}{y}, args[1:]...)
+	pass, t.why, t.err = runMatcher(t.m, margs)
+	if !pass && t.err == nil {
+		t.why = fmt.Sprintf("transformed to %#v\n%s", y, nested(t.m, t.why))
+	}
+	return
+}
+
+func (t *transform) String() string {
+	if !t.fn.IsValid() {
+		return fmt.Sprintf("WithTransform(nil, %s)", t.m)
+	}
+	return fmt.Sprintf("WithTransform(%s, %s)", t.fn.Type(), t.m)
+}
+func (t *transform) Error() error   { return t.err }
+func (t *transform) clone() Matcher { return &transform{fn: t.fn, m: cloneMatcher(t.m)} }
+func (t *transform) NumIn() int     { return t.m.NumIn() }
+func (t *transform) IsVariadic() bool {
+	v, ok := t.m.(VariadicMatcher)
+	return ok && v.IsVariadic()
+}
+func (t *transform) explain(args []interface{}) string { return t.why }
+
+type binding struct {
+	m    Matcher
+	args []interface{}
+	err  error
+	why  string
+}
+
+//  Create a Matcher of just the object by binding the arguments of m, for
+//  use where a Matcher can't be given arguments, like MatchFields.
+//      MatchFields(map[string]Matcher{"Age": Bind(BeGreaterThan, 17)}, IgnoreExtras)
+func Bind(m Matcher, args ...interface{}) Matcher { return &binding{m: m, args: args} }
+
+func (b *binding) Matches(args []interface{}) (pass bool, err error) {
+	b.err, b.why = nil, ""
+	if len(args) != 1 {
+		return false, errors.New("wrong number of arguments")
+	}
+	if !acceptsNumIn(b.m, len(b.args)+1) {
+		return false, fmt.Errorf("Bind can't give %s %d arguments", b.m, len(b.args))
+	}
+	pass, b.why, b.err = runMatcher(b.m, append([]interface{}{args[0]}, b.args...))
+	return
+}
+
+func (b *binding) String() string {
+	s := []string{b.m.String()}
+	for _, arg := range b.args {
+		s = append(s, formatSpecValue(arg))
+	}
+	return strings.Join(s, " ")
+}
+func (b *binding) Error() error                      { return b.err }
+func (b *binding) clone() Matcher                    { return &binding{m: cloneMatcher(b.m), args: cloneArgs(b.args)} }
+func (b *binding) NumIn() int                        { return 1 }
+func (b *binding) explain(args []interface{}) string { return b.why }
@@ -0,0 +1,65 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    combinator_test.go
+ *  Description: For testing combinator.go
+ */
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCombinators(T *testing.T) {
+	length := func(s string) int { return len(s) }
+	describePassing(T, "Combinators", func(s *SpecTest) {
+		s.It("match", func() {
+			s.Spec(5, Should, AllOf(BeGreaterThan, BeLessThan), 0, 10)
+			s.Spec(15, Should, Not, AllOf(BeGreaterThan, BeLessThan), 0, 10)
+			s.Spec(io.EOF, Should, AnyOf(HaveErrorMatching, HaveErrorMatching), io.ErrUnexpectedEOF, io.EOF)
+			s.Spec(5, Should, Not, And(BeNaN, BeInf))
+			s.Spec(5, Should, Or(Negate(Equal), BeBetween), 5, 1, 10)
+			s.Spec(4, Should, Negate(Equal), 5)
+			s.Spec("abc", Should, WithTransform(length, Equal), 3)
+			s.Spec(func() string { return "ab" }, Should, WithTransform(length, BeBetween), 1, 2)
+			s.Spec([]int{1, 2}, Should, AllOf(Contain, Contain), 1, 2)
+		})
+	})
+}
+
+func TestCombinatorExplanations(T *testing.T) {
+	length := func(s string) int { return len(s) }
+	root := runDescribed("Combinators", func(s *SpecTest) {
+		s.It("explain failures", func() {
+			s.Spec(15, Should, AllOf(BeGreaterThan, BeLessThan), 0, 10)
+			s.Spec(15, Should, AnyOf(BeLessThan, Equal), 10, 16)
+			s.Spec(5, Should, Negate(Equal), 5)
+			s.Spec("abcd", Should, WithTransform(length, Equal), 3)
+		})
+		s.It("report errors", func() {
+			s.Spec(1, Should, AllOf(BeGreaterThan, HavePrefix), 0, "a")
+			s.Spec(1, Should, AllOf(PanicMatching, Equal), Equal, 1)
+			s.Spec(1, Should, WithTransform(length, Equal), 1)
+			s.Spec(1, Should, WithTransform(nil, Equal), 1)
+			s.Spec(1, Should, AllOf())
+		})
+	})
+	expectResults(T, root.Children[0], Failed,
+		"BeLessThan failed\n\t15 > 10",
+		"BeLessThan failed\n\t15 > 10\nEqual failed\n\t15 != 16",
+		"Equal matched",
+		"transformed to 4\nEqual failed\n\t4 != 3")
+	expectResults(T, root.Children[1], Errored,
+		"HavePrefix needs a string, []byte, fmt.Stringer or error, not int",
+		"AllOf can only have a variadic Matcher last",
+		"WithTransform can't give 1 to a func(string) int",
+		"WithTransform needs a function of one argument and one return value",
+		"AllOf needs a Matcher")
+	if r := root.Children[0].Results; len(r) > 0 && !strings.Contains(r[0].Spec, "AllOf(BeGreaterThan, BeLessThan) 0 10") {
+		T.Errorf("unexpected spec: %s", r[0].Spec)
+	}
+}
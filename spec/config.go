@@ -0,0 +1,93 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    config.go
+ *  Description: Options selecting and ordering the specs of a SpecTest.
+ */
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"regexp"
+	"strings"
+)
+
+//  The options of a SpecTest. The zero Config executes every leaf in the
+//  order it was declared. Patterns are matched against the full description
+//  of a leaf (see Node.String). A label expression like "db && !slow"
+//  combines labels (see SpecTest.Labels) with the operators !, && and ||,
+//  and parentheses.
+type Config struct {
+	Pattern     string   // Only leaves matching this regexp are executed.
+	SkipPattern string   // Leaves matching this regexp are not executed.
+	Labels      string   // Only leaves whose labels satisfy this expression are executed.
+	Seed        int64    // When nonzero, the blocks of a Describe run in a random order.
+	Debug       bool     // Log how Spec sequences are parsed.
+	Subtests    bool     // Run blocks as subtests (see NewSubtestSpecTest).
+	Reporter    Reporter // Receives the results of every executed leaf.
+}
+
+//  The Config used by NewSpecTest. The Pattern, SkipPattern and Labels are
+//  read from the environment variables GOSPECPATTERN, GOSPECSKIP and
+//  GOSPECLABELS, which are set by the gospec command.
+func DefaultConfig() Config {
+	return Config{
+		Pattern:     os.Getenv("GOSPECPATTERN"),
+		SkipPattern: os.Getenv("GOSPECSKIP"),
+		Labels:      os.Getenv("GOSPECLABELS"),
+	}
+}
+
+//  Create a new SpecTest with the given options.
+//      func TestObject(T *testing.T) {
+//          config := DefaultConfig()
+//          config.SkipPattern = "slowly"
+//          s := NewSpecTestConfig(T, config)
+//          ...
+//      }
+//  Subtests are only run when T is a *testing.T.
+func NewSpecTestConfig(T Test, config Config) *SpecTest {
+	t := &SpecTest{Test: T, config: config}
+	t.pattern, t.configErr = compilePattern("pattern", config.Pattern)
+	if t.configErr == nil {
+		t.skipPattern, t.configErr = compilePattern("skip pattern", config.SkipPattern)
+	}
+	if t.configErr == nil && strings.TrimSpace(config.Labels) != "" {
+		t.labels, t.configErr = compileLabels(config.Labels)
+	}
+	if config.Seed != 0 {
+		t.rand = rand.New(rand.NewSource(config.Seed))
+	}
+	return t
+}
+
+//  Compile a non-empty pattern of a Config.
+func compilePattern(name, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("Can't compile %s %q: %v", name, pattern, err)
+	}
+	return re, nil
+}
+
+//  Returns true if the configuration of t selects the leaf n.
+func (t *SpecTest) selects(n *Node) bool {
+	desc := n.String()
+	if t.pattern != nil && !t.pattern.MatchString(desc) {
+		return false
+	}
+	if t.labels != nil && !t.labels(n.labelSet()) {
+		return false
+	}
+	return t.skipPattern == nil || !t.skipPattern.MatchString(desc)
+}
+
+//  The Config of t.
+func (t *SpecTest) Config() Config { return t.config }
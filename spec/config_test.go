@@ -0,0 +1,116 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    config_test.go
+ *  Description: For testing config.go
+ */
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+//  Run a tree of leaves a, b, c and d with a configuration. Returns the
+//  leaves that were executed.
+func runConfig(config Config) (ran []string, mock *mockTest) {
+	mock = new(mockTest)
+	s := NewSpecTestConfig(mock, config)
+	mark := marker(&ran)
+	s.Describe("config", func() {
+		s.It("a", mark("a"))
+		s.It("b", mark("b"))
+		s.Describe("c", func() {
+			s.It("d", mark("d"))
+		})
+	})
+	return
+}
+
+func TestConfigPatterns(T *testing.T) {
+	for _, test := range []struct {
+		config Config
+		ran    string
+	}{
+		{Config{}, "a b d"},
+		{Config{Pattern: "config [ab]"}, "a b"},
+		{Config{SkipPattern: "b$"}, "a d"},
+		{Config{Pattern: "config [ab]", SkipPattern: "b$"}, "a"},
+	} {
+		ran, mock := runConfig(test.config)
+		if s := strings.Join(ran, " "); s != test.ran || len(mock.errors) != 0 {
+			T.Errorf("%+v: ran %q with errors %q", test.config, s, mock.errors)
+		}
+	}
+
+	// Different SpecTests use different patterns.
+	a, _ := runConfig(Config{Pattern: "a"})
+	b, _ := runConfig(Config{Pattern: "b"})
+	if len(a) != 1 || a[0] != "a" || len(b) != 1 || b[0] != "b" {
+		T.Errorf("ran %q and %q", a, b)
+	}
+
+	ran, mock := runConfig(Config{SkipPattern: "("})
+	if len(ran) != 0 || len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "Can't compile skip pattern") {
+		T.Errorf("ran %q with errors %q", ran, mock.errors)
+	}
+}
+
+func TestConfigSeed(T *testing.T) {
+	orders := make(map[string]bool)
+	for seed := int64(1); seed <= 20; seed++ {
+		ran, _ := runConfig(Config{Seed: seed})
+		again, _ := runConfig(Config{Seed: seed})
+		if s := strings.Join(ran, " "); s != strings.Join(again, " ") {
+			T.Errorf("seed %d: ran %q then %q", seed, ran, again)
+		}
+		orders[strings.Join(ran, " ")] = true
+		sort.Strings(ran)
+		if s := strings.Join(ran, " "); s != "a b d" {
+			T.Errorf("seed %d: ran %q", seed, s)
+		}
+	}
+	if len(orders) < 2 {
+		T.Errorf("seeds gave a single order %v", orders)
+	}
+}
+
+type mockReporter []string
+
+func (r *mockReporter) Report(leaf *Node, results []SpecResult) {
+	*r = append(*r, leaf.String()+": "+blockOutcome(countOutcomes(results)).String())
+}
+
+func TestConfigReporter(T *testing.T) {
+	r := new(mockReporter)
+	s := NewSpecTestConfig(new(mockTest), Config{Reporter: r})
+	s.Describe("reported", func() {
+		s.It("passes", func() { s.Spec(1, Should, Equal, 1) })
+		s.It("fails", func() { s.Spec(1, Should, Equal, 2) })
+	})
+	if s := strings.Join(*r, ", "); s != "reported passes: PASS, reported fails: FAIL" {
+		T.Errorf("reported %q", s)
+	}
+}
+
+func TestDefaultConfig(T *testing.T) {
+	for _, env := range []string{"GOSPECPATTERN", "GOSPECSKIP", "GOSPECLABELS"} {
+		defer os.Setenv(env, os.Getenv(env))
+	}
+	os.Setenv("GOSPECPATTERN", "b")
+	os.Setenv("GOSPECSKIP", "c")
+	os.Setenv("GOSPECLABELS", "!slow")
+	if c := DefaultConfig(); c.Pattern != "b" || c.SkipPattern != "c" || c.Labels != "!slow" {
+		T.Errorf("unexpected default %+v", c)
+	}
+	if c := NewSpecTest(new(mockTest)).Config(); c.Pattern != "b" || c.Subtests {
+		T.Errorf("unexpected NewSpecTest config %+v", c)
+	}
+	if c := NewSubtestSpecTest(T).Config(); c.Pattern != "b" || !c.Subtests {
+		T.Errorf("unexpected NewSubtestSpecTest config %+v", c)
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    diff.go
+ *  Description: Describe the differences between two values.
+ */
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//  The maximum number of differences described by diffValues.
+const maxDiffs = 20
+
+//  The number of unchanged lines shown around changed lines of strings.
+const diffContext = 2
+
+//  Describe how a differs from b, one difference per line. Each difference is
+//  annotated with the path (field, index or key) leading to it.
+//      .Items[3].Name: "a" != "b"
+//      ["key"]: unexpected 2
+//      ["other"]: missing 3
+func diffValues(a, b interface{}) string {
+	return exactly.diffValues(a, b)
+}
+
+//  A pair of pointers that has been compared.
+type visit struct {
+	a, b uintptr
+	typ  reflect.Type
+}
+
+type differ struct {
+	*equality
+	lines   []string
+	limit   int // Stop after finding limit differences.
+	visited map[visit]bool
+}
+
+func (eq *equality) differ(limit int) *differ {
+	return &differ{equality: eq, limit: limit, visited: make(map[visit]bool)}
+}
+
+//  Describe how a differs from b according to eq. See diffValues.
+func (eq *equality) diffValues(a, b interface{}) string {
+	d := eq.differ(maxDiffs + 1)
+	d.diff("", reflect.ValueOf(a), reflect.ValueOf(b))
+	if len(d.lines) > maxDiffs {
+		d.lines = append(d.lines[:maxDiffs], "... more differences")
+	}
+	return strings.Join(d.lines, "\n")
+}
+
+//  Returns true if a and b are equal according to eq.
+func (eq *equality) equal(a, b reflect.Value) bool {
+	d := eq.differ(1)
+	d.diff("", a, b)
+	return len(d.lines) == 0
+}
+
+//  Format a value for a difference message.
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+func (d *differ) addf(path, format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+	if path != "" {
+		msg = path + ": " + msg
+	}
+	d.lines = append(d.lines, msg)
+}
+
+func (d *differ) mismatch(path string, a, b reflect.Value) {
+	d.addf(path, "%s != %s", formatValue(a), formatValue(b))
+}
+
+func (d *differ) diff(path string, a, b reflect.Value) {
+	if len(d.lines) >= d.limit {
+		return
+	}
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			d.mismatch(path, a, b)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		d.addf(path, "type %s != %s", a.Type(), b.Type())
+		return
+	}
+	if cmp, ok := d.comparers[a.Type()]; ok && a.CanInterface() && b.CanInterface() {
+		if !cmp.Call([]reflect.Value{a, b})[0].Bool() {
+			d.mismatch(path, a, b)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Map, reflect.Slice:
+		if d.nilEmpty && a.Len() == 0 && b.Len() == 0 {
+			return
+		}
+	}
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if a.IsNil() != b.IsNil() {
+			d.mismatch(path, a, b)
+			return
+		}
+		if a.Pointer() == b.Pointer() && (a.Kind() != reflect.Slice || a.Len() == b.Len()) {
+			return
+		}
+		v := visit{a.Pointer(), b.Pointer(), a.Type()}
+		if d.visited[v] {
+			return
+		}
+		d.visited[v] = true
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.mismatch(path, a, b)
+			}
+			return
+		}
+		d.diff(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i, n := 0, a.NumField(); i < n; i++ {
+			if d.ignored(a.Type(), a.Type().Field(i)) {
+				continue
+			}
+			d.diff(path+"."+a.Type().Field(i).Name, a.Field(i), b.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if d.unordered {
+			d.diffBag(path, a, b)
+			return
+		}
+		d.diffSeq(path, a, b)
+	case reflect.Map:
+		d.diffMap(path, a, b)
+	case reflect.String:
+		if a.String() == b.String() {
+			return
+		}
+		if strings.Contains(a.String(), "\n") || strings.Contains(b.String(), "\n") {
+			d.addf(path, "strings differ (-expected +actual)\n%s", diffLines(b.String(), a.String()))
+			return
+		}
+		d.mismatch(path, a, b)
+	case reflect.Func:
+		if !a.IsNil() || !b.IsNil() {
+			d.addf(path, "functions are only equal when nil")
+		}
+	default:
+		if !d.scalarEqual(a, b) {
+			d.mismatch(path, a, b)
+		}
+	}
+}
+
+func (d *differ) diffSeq(path string, a, b reflect.Value) {
+	n, m := a.Len(), b.Len()
+	for i := 0; i < n && i < m; i++ {
+		d.diff(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i))
+	}
+	for i := m; i < n; i++ {
+		d.addf(fmt.Sprintf("%s[%d]", path, i), "unexpected %s", formatValue(a.Index(i)))
+	}
+	for i := n; i < m; i++ {
+		d.addf(fmt.Sprintf("%s[%d]", path, i), "missing %s", formatValue(b.Index(i)))
+	}
+}
+
+//  Compare a and b as unordered collections, pairing each element of a with
+//  an equal element of b.
+func (d *differ) diffBag(path string, a, b reflect.Value) {
+	paired := make([]bool, b.Len())
+	for i := 0; i < a.Len(); i++ {
+		found := false
+		for j := range paired {
+			if !paired[j] && d.equal(a.Index(i), b.Index(j)) {
+				paired[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			d.addf(fmt.Sprintf("%s[%d]", path, i), "unexpected %s", formatValue(a.Index(i)))
+		}
+	}
+	for j, ok := range paired {
+		if !ok {
+			d.addf(path, "missing %s", formatValue(b.Index(j)))
+		}
+	}
+}
+
+func (d *differ) diffMap(path string, a, b reflect.Value) {
+	keys := a.MapKeys()
+	for _, k := range b.MapKeys() {
+		if !a.MapIndex(k).IsValid() {
+			keys = append(keys, k)
+		}
+	}
+	sortValues(keys)
+	for _, k := range keys {
+		kpath := fmt.Sprintf("%s[%s]", path, formatValue(k))
+		av, bv := a.MapIndex(k), b.MapIndex(k)
+		switch {
+		case !bv.IsValid():
+			d.addf(kpath, "unexpected %s", formatValue(av))
+		case !av.IsValid():
+			d.addf(kpath, "missing %s", formatValue(bv))
+		default:
+			d.diff(kpath, av, bv)
+		}
+	}
+}
+
+//  Sort values by their formatted representation.
+func sortValues(vals []reflect.Value) {
+	sort.Slice(vals, func(i, j int) bool {
+		return formatValue(vals[i]) < formatValue(vals[j])
+	})
+}
+
+//  Compare values of a basic kind without calling Interface, which is not
+//  allowed for unexported struct fields.
+func (eq *equality) scalarEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float() || math.Abs(a.Float()-b.Float()) <= eq.tolerance
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex() || cmplx.Abs(a.Complex()-b.Complex()) <= eq.tolerance
+	case reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	}
+	return true
+}
+
+//  A line-by-line diff of two strings. Removed lines are prefixed with "-",
+//  added lines with "+". Long runs of unchanged lines are elided.
+func diffLines(a, b string) string {
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+	n, m := len(x), len(y)
+
+	// Length of the longest common subsequence of x[i:] and y[j:].
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && x[i] == y[j]:
+			lines = append(lines, line{' ', x[i]})
+			i++
+			j++
+		case j == m || i < n && lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, line{'-', x[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', y[j]})
+			j++
+		}
+	}
+
+	// Keep unchanged lines within diffContext lines of a change.
+	keep := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for c := k - diffContext; c <= k+diffContext; c++ {
+			if c >= 0 && c < len(lines) {
+				keep[c] = true
+			}
+		}
+	}
+	var out []string
+	elided := false
+	for k, l := range lines {
+		if !keep[k] {
+			if !elided {
+				out = append(out, "\t...")
+			}
+			elided = true
+			continue
+		}
+		elided = false
+		out = append(out, fmt.Sprintf("\t%c %s", l.op, l.text))
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,66 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    diff_test.go
+ *  Description: For testing diff.go
+ */
+
+import (
+	"strings"
+	"testing"
+)
+
+type diffItem struct {
+	Name string
+	tags map[string]int
+}
+
+type diffList struct {
+	Items []diffItem
+}
+
+func TestDiffValues(T *testing.T) {
+	a := diffList{[]diffItem{{"x", nil}, {"a", map[string]int{"k": 1, "u": 2}}}}
+	b := diffList{[]diffItem{{"x", nil}, {"b", map[string]int{"k": 2, "m": 3}}, {"c", nil}}}
+	expect := []string{
+		`.Items[1].Name: "a" != "b"`,
+		`.Items[1].tags["k"]: 1 != 2`,
+		`.Items[1].tags["m"]: missing 3`,
+		`.Items[1].tags["u"]: unexpected 2`,
+		`.Items[2]: missing spec.diffItem{Name:"c", tags:map[string]int(nil)}`,
+	}
+	if d := diffValues(a, b); d != strings.Join(expect, "\n") {
+		T.Errorf("unexpected diff\n%s", d)
+	}
+
+	if d := diffValues(1, int64(1)); d != "type int != int64" {
+		T.Errorf("unexpected diff %q", d)
+	}
+	if d := diffValues([]int{}, []int(nil)); d != "[]int{} != []int(nil)" {
+		T.Errorf("unexpected diff %q", d)
+	}
+}
+
+func TestDiffLines(T *testing.T) {
+	d := diffValues("a\nb\nc\nd\ne\nf\ng", "a\nb\nc\nd\ne\nF\ng")
+	expect := "strings differ (-expected +actual)\n\t...\n\t  d\n\t  e\n\t- F\n\t+ f\n\t  g"
+	if d != expect {
+		T.Errorf("unexpected diff\n%s", d)
+	}
+}
+
+func TestEqualDetail(T *testing.T) {
+	mock := new(mockTest)
+	s := NewSpecTestConfig(mock, Config{})
+	s.Describe("Equal", func() {
+		s.It("explains failures", func() {
+			s.Spec(diffItem{Name: "a"}, Should, Equal, diffItem{Name: "b"})
+		})
+	})
+	if len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "\n\t\t.Name: \"a\" != \"b\"") {
+		T.Errorf("unexpected errors %q", mock.errors)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    equality.go
+ *  Description: Deep equality Matchers with configurable comparisons.
+ */
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+//  Options controlling how EqualWith compares values.
+type equality struct {
+	ignore     map[string]bool                // Field names ("Field" or "Type.Field") to skip.
+	unexported bool                           // Skip unexported struct fields.
+	unordered  bool                           // Compare slices and arrays as multisets.
+	nilEmpty   bool                           // Nil and empty slices (maps) are equal.
+	tolerance  float64                        // Maximum difference of equal floats.
+	comparers  map[reflect.Type]reflect.Value // Functions func(a, b T) bool.
+	err        error                          // A bad option.
+}
+
+//  The options of Equal.
+var exactly = new(equality)
+
+//  Returns true if field f of struct type typ is not compared.
+func (eq *equality) ignored(typ reflect.Type, f reflect.StructField) bool {
+	if eq.unexported && f.PkgPath != "" {
+		return true
+	}
+	return eq.ignore[f.Name] || eq.ignore[typ.Name()+"."+f.Name]
+}
+
+//  An option for EqualWith.
+type EqualOption func(*equality)
+
+//  Don't compare the named struct fields. A name can be qualified by the
+//  name of a struct type ("Record.ID") to ignore only fields of that type.
+func IgnoreFields(names ...string) EqualOption {
+	return func(eq *equality) {
+		if eq.ignore == nil {
+			eq.ignore = make(map[string]bool)
+		}
+		for _, name := range names {
+			eq.ignore[name] = true
+		}
+	}
+}
+
+//  Don't compare unexported struct fields.
+func IgnoreUnexported() EqualOption {
+	return func(eq *equality) { eq.unexported = true }
+}
+
+//  Compare slices and arrays as multisets, ignoring the order of elements.
+func IgnoreOrder() EqualOption {
+	return func(eq *equality) { eq.unordered = true }
+}
+
+//  Consider nil and empty slices equal, and nil and empty maps equal.
+func NilEqualsEmpty() EqualOption {
+	return func(eq *equality) { eq.nilEmpty = true }
+}
+
+//  Consider floating point (and complex) numbers equal when they differ by
+//  no more than tolerance.
+func FloatTolerance(tolerance float64) EqualOption {
+	return func(eq *equality) {
+		if tolerance < 0 {
+			eq.err = errors.New("FloatTolerance is negative")
+		}
+		eq.tolerance = tolerance
+	}
+}
+
+//  Compare values of type T with a function fn of type func(a, b T) bool.
+func Comparer(fn interface{}) EqualOption {
+	return func(eq *equality) {
+		typ := reflect.TypeOf(fn)
+		if typ == nil || typ.Kind() != reflect.Func ||
+			typ.NumIn() != 2 || typ.In(0) != typ.In(1) ||
+			typ.NumOut() != 1 || typ.Out(0) != boolType {
+			eq.err = fmt.Errorf("Comparer needs a function func(a, b T) bool, not %v", typ)
+			return
+		}
+		if eq.comparers == nil {
+			eq.comparers = make(map[reflect.Type]reflect.Value)
+		}
+		eq.comparers[typ.In(0)] = reflect.ValueOf(fn)
+	}
+}
+
+//  Create a deep equality Matcher like Equal that compares values according
+//  to the given options.
+//      s.Spec(record, Should, EqualWith(IgnoreFields("ID", "Created")), expected)
+//      s.Spec(names, Should, EqualWith(IgnoreOrder()), []string{"a", "b"})
+func EqualWith(opts ...EqualOption) Matcher {
+	eq := new(equality)
+	for _, opt := range opts {
+		opt(eq)
+	}
+	m := MatcherMust(NewMatcher("EqualWith", func(a, b interface{}) (bool, error) {
+		if eq.err != nil {
+			return false, eq.err
+		}
+		return eq.equal(
+			reflect.ValueOf(valueOfSpecValue(a)),
+			reflect.ValueOf(valueOfSpecValue(b))), nil
+	}))
+	return explained(m, func(args []interface{}) string {
+		return eq.diffValues(valueOfSpecValue(args[0]), valueOfSpecValue(args[1]))
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    equality_test.go
+ *  Description: For testing equality.go
+ */
+
+import (
+	"strings"
+	"testing"
+)
+
+type equalityRecord struct {
+	ID     int
+	Name   string
+	Tags   []string
+	Score  float64
+	secret string
+}
+
+func TestEqualWith(T *testing.T) {
+	x, y := 0.1, 0.2
+	a := equalityRecord{1, "a", []string{"x", "y"}, 0.3, "p"}
+	b := equalityRecord{2, "a", []string{"y", "x"}, x + y, "q"}
+	for _, test := range []struct {
+		m      Matcher
+		expect bool
+	}{
+		{Equal, false},
+		{EqualWith(IgnoreFields("ID"), IgnoreUnexported(), IgnoreOrder()), false},
+		{EqualWith(IgnoreFields("equalityRecord.ID"), IgnoreUnexported(), IgnoreOrder(), FloatTolerance(1e-9)), true},
+		{EqualWith(IgnoreFields("ID"), IgnoreOrder(), FloatTolerance(1e-9)), false},
+		{EqualWith(
+			Comparer(func(a, b equalityRecord) bool { return a.Name == b.Name })), true},
+	} {
+		pass, err := test.m.Matches([]interface{}{a, b})
+		if err != nil {
+			T.Errorf("%s: %v", test.m, err)
+		} else if pass != test.expect {
+			T.Errorf("%s: %v != %v", test.m, pass, test.expect)
+		}
+	}
+
+	m := EqualWith(NilEqualsEmpty())
+	if pass, _ := m.Matches([]interface{}{[]int{}, []int(nil)}); !pass {
+		T.Error("empty slice not equal to nil")
+	}
+	if pass, _ := m.Matches([]interface{}{map[int]int{}, map[int]int(nil)}); !pass {
+		T.Error("empty map not equal to nil")
+	}
+
+	m = EqualWith(Comparer(1))
+	if m.Matches([]interface{}{1, 1}); m.Error() == nil {
+		T.Error("Comparer accepted a non-function")
+	}
+}
+
+func TestEqualWithDetail(T *testing.T) {
+	m := EqualWith(IgnoreOrder())
+	d := m.(explainer).explain([]interface{}{[]int{1, 2, 3}, []int{3, 4, 1}})
+	if d != "[1]: unexpected 2\nmissing 4" {
+		T.Errorf("unexpected explanation %q", d)
+	}
+	d = EqualWith(IgnoreFields("ID")).(explainer).explain([]interface{}{
+		equalityRecord{ID: 1, Name: "a"},
+		equalityRecord{ID: 2, Name: "b"}})
+	if strings.Contains(d, "ID") || !strings.Contains(d, `.Name: "a" != "b"`) {
+		T.Errorf("unexpected explanation %q", d)
+	}
+}
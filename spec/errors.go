@@ -0,0 +1,109 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    errors.go
+ *  Description: Matchers inspecting errors with errors.Is and errors.As.
+ */
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+//  Matchers for errors. The object can be an error value or a call of a
+//  function whose last return value is an error.
+//      s.Spec(func() (int, error) { return f.Read(p) }, Should, HaveErrorMatching, io.EOF)
+//      s.Spec(err, Should, HaveErrorOfType, new(*os.PathError))
+//      s.Spec(err, Should, HaveErrorMessage, `no such file`)
+//  HaveErrorMatching uses errors.Is. HaveErrorOfType uses errors.As with
+//  its argument, a non-nil pointer to an error type, which receives the
+//  matching error. HaveErrorMessage matches the error's message against a
+//  regular expression. A nil error matches none of them.
+var (
+	HaveErrorMatching = explained(MatcherMust(NewMatcher("HaveErrorMatching", matcherHaveErrorMatching)), explainErrorChain)
+	HaveErrorOfType   = explained(MatcherMust(NewMatcher("HaveErrorOfType", matcherHaveErrorOfType)), explainErrorChain)
+	HaveErrorMessage  = explained(MatcherMust(NewMatcher("HaveErrorMessage", matcherHaveErrorMessage)), explainErrorChain)
+)
+
+//  The error of an error Spec value or function call.
+func errorOf(name string, x interface{}) (err, bad error) {
+	if fn, ok := x.(FnCall); ok {
+		typ := fn.fn.Type()
+		if n := typ.NumOut(); n == 0 || !errorType.AssignableTo(typ.Out(n-1)) {
+			return nil, fmt.Errorf("%s needs a function returning an error last", name)
+		}
+		if fn.panicv != nil {
+			return nil, fnerror{fn}
+		}
+		x = fn.out[len(fn.out)-1].Interface()
+	}
+	switch e := x.(type) {
+	case nil:
+		return nil, nil
+	case error:
+		return e, nil
+	}
+	return nil, fmt.Errorf("%s needs an error or a function call returning one, not %T", name, x)
+}
+
+func matcherHaveErrorMatching(x, target interface{}) (pass bool, err error) {
+	e, err := errorOf("HaveErrorMatching", x)
+	if err != nil {
+		return
+	}
+	t, ok := target.(error)
+	if !ok {
+		return false, fmt.Errorf("HaveErrorMatching needs an error to match, not %T", target)
+	}
+	return e != nil && errors.Is(e, t), nil
+}
+
+func matcherHaveErrorOfType(x, target interface{}) (pass bool, err error) {
+	e, err := errorOf("HaveErrorOfType", x)
+	if err != nil {
+		return
+	}
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return false, fmt.Errorf("HaveErrorOfType needs a non-nil pointer, not %T", target)
+	}
+	if typ := v.Type().Elem(); typ.Kind() != reflect.Interface && !typ.Implements(reflect.TypeOf(&e).Elem()) {
+		return false, fmt.Errorf("HaveErrorOfType target type %s is not an error", typ)
+	}
+	return e != nil && errors.As(e, target), nil
+}
+
+func matcherHaveErrorMessage(x, pattern interface{}) (pass bool, err error) {
+	e, err := errorOf("HaveErrorMessage", x)
+	if err != nil {
+		return
+	}
+	p, err := stringOf("HaveErrorMessage", pattern)
+	if err != nil {
+		return
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return false, fmt.Errorf("HaveErrorMessage can't compile %q: %v", p, err)
+	}
+	return e != nil && re.MatchString(e.Error()), nil
+}
+
+//  Describe the chain of errors wrapped by the object's error.
+func explainErrorChain(args []interface{}) string {
+	e, _ := errorOf("", args[0])
+	if e == nil {
+		return "no error"
+	}
+	var chain []string
+	for ; e != nil; e = errors.Unwrap(e) {
+		chain = append(chain, fmt.Sprintf("%T: %q", e, e.Error()))
+	}
+	return "errors:\n\t" + strings.Join(chain, "\n\t")
+}
@@ -0,0 +1,62 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    errors_test.go
+ *  Description: For testing errors.go
+ */
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestErrorMatchers(T *testing.T) {
+	wrapped := fmt.Errorf("reading config: %w", &os.PathError{Op: "open", Path: "x", Err: io.EOF})
+	read := func() (int, error) { return 0, wrapped }
+	var perr *os.PathError
+	describePassing(T, "Error matchers", func(s *SpecTest) {
+		s.It("match", func() {
+			s.Spec(read, Should, HaveErrorMatching, io.EOF)
+			s.Spec(wrapped, Should, Not, HaveErrorMatching, io.ErrUnexpectedEOF)
+			s.Spec(nil, Should, Not, HaveErrorMatching, io.EOF)
+			s.Spec(read, Should, HaveErrorOfType, &perr)
+			s.Spec(perr.Path, Should, Equal, "x")
+			s.Spec(errors.New("x"), Should, Not, HaveErrorOfType, &perr)
+			s.Spec(wrapped, Should, HaveErrorMessage, `^reading config: open x`)
+			s.Spec(func() error { return nil }, Should, Not, HaveErrorMessage, ``)
+		})
+	})
+}
+
+func TestErrorMatcherErrors(T *testing.T) {
+	var perr os.PathError
+	for _, test := range []struct {
+		m    Matcher
+		args []interface{}
+	}{
+		{HaveErrorMatching, []interface{}{1, io.EOF}},
+		{HaveErrorMatching, []interface{}{io.EOF, "EOF"}},
+		{HaveErrorOfType, []interface{}{io.EOF, perr}},
+		{HaveErrorOfType, []interface{}{io.EOF, new(string)}},
+		{HaveErrorMessage, []interface{}{io.EOF, `(`}},
+	} {
+		if _, err := test.m.Matches(test.args); err == nil && test.m.Error() == nil {
+			T.Errorf("%s accepted %#v", test.m, test.args)
+		}
+	}
+}
+
+func TestErrorChainExplanation(T *testing.T) {
+	wrapped := fmt.Errorf("reading: %w", io.EOF)
+	s := HaveErrorMatching.(explainer).explain([]interface{}{wrapped, io.ErrClosedPipe})
+	if !strings.Contains(s, `*fmt.wrapError: "reading: EOF"`) || !strings.Contains(s, `*errors.errorString: "EOF"`) {
+		T.Errorf("unexpected explanation %q", s)
+	}
+}
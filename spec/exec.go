@@ -9,33 +9,34 @@ package spec
 import (
 	"errors"
 	"reflect"
+	"fmt"
 )
 
-func (t *SpecTest) exec(m Matcher, negated bool, args []interface{}) {
+//  Run a parsed Spec. Returns whether the Spec passed.
+func (t *SpecTest) exec(m Matcher, negated bool, args []interface{}) (passed bool, err error) {
 	if len(args) < 1 {
 		// Serious error
-		t.Errorf("Spec error: Missing Value")
-		return
-	}
-	if negated {
-		defer func() { t.passed = !t.passed }()
+		return false, ErrMissingValue
 	}
 
 	if n := len(args); n < m.NumIn() {
-		t.Errorf("Spec error: Missing argument")
-		return
-	} else if n > m.NumIn() {
-		t.Errorf("Spec error: Unexpected arguments %v", args[n:])
-		return
+		return false, errors.New("Missing argument")
+	} else if !acceptsNumIn(m, n) {
+		return false, fmt.Errorf("Unexpected arguments %v", args[m.NumIn():])
+	}
+	passed, err = m.Matches(args)
+	if err == nil {
+		err = m.Error()
+	}
+	if err != nil {
+		// The Spec is an error, whether or not it was negated.
+		return false, err
 	}
-    t.passed, t.err = m.Matches(args)
-    if t.err == nil {
-        t.err = m.Error()
-    }
-    if t.err != nil {
-        panic(t.err)
-    }
-    // Matcher errors messages are handled in Describe
+	if negated {
+		passed = !passed
+	}
+	// Matcher errors messages are handled by the leaf's report.
+	return
 }
 
 func (t *SpecTest) equal(a, b interface{}) (bool, error) {
@@ -0,0 +1,267 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    fields.go
+ *  Description: Matchers for the fields of structs.
+ */
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//  Match a field of the object, found by a path of names separated by
+//  dots. Each name is an exported struct field, a key of a map with
+//  string keys, or a method with no arguments and one return value. A name
+//  ending in "()" is always a method. The field is matched with the Matcher
+//  and arguments following the path, or is compared with Equal to a value.
+//      s.Spec(user, Should, HaveField, "Address.City", Equal, "Paris")
+//      s.Spec(user, Should, HaveField, "Name()", HavePrefix, "Go")
+//      s.Spec(config, Should, HaveField, "Env.HOME", "/root")
+var HaveField Matcher = new(haveField)
+
+//  What MatchFields does with fields that only one of the struct and the
+//  Matchers have.
+type Strictness uint8
+
+const (
+	Strictly      Strictness = 0      // Every exported field has a Matcher and every Matcher a field.
+	IgnoreExtras  Strictness = 1 << 0 // Exported fields without a Matcher are ignored.
+	IgnoreMissing Strictness = 1 << 1 // Matchers for fields the struct doesn't have are ignored.
+)
+
+//  Create a Matcher checking the fields of a struct. The keys of fields are
+//  field paths like those of HaveField. The Matchers take no arguments
+//  besides the field; use Bind to give them arguments. A failure lists
+//  each field that did not match.
+//      s.Spec(user, Should, MatchFields(map[string]Matcher{
+//          "Name": Bind(Equal, "gopher"),
+//          "Age":  Bind(BeBetween, 1, 10),
+//      }, IgnoreExtras))
+func MatchFields(fields map[string]Matcher, strictness Strictness) Matcher {
+	return &matchFields{fields: fields, strictness: strictness}
+}
+
+//  A name in a field path that wasn't found.
+type missingField struct {
+	name string
+	typ  reflect.Type
+}
+
+func (e missingField) Error() string {
+	return fmt.Sprintf("no field, key or method %s in %s", e.name, e.typ)
+}
+
+//  Find the field of v at path.
+func walkField(v reflect.Value, path string) (field reflect.Value, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = recovered(e)
+		}
+	}()
+	for _, name := range strings.Split(path, ".") {
+		call := strings.HasSuffix(name, "()")
+		if v, err = fieldStep(v, strings.TrimSuffix(name, "()"), call); err != nil {
+			return
+		}
+	}
+	return v, nil
+}
+
+//  Find the field, key or method called name in v.
+func fieldStep(v reflect.Value, name string, call bool) (reflect.Value, error) {
+	v = unwrapInterface(v)
+	if !v.IsValid() || canBeNil(v.Kind()) && v.IsNil() {
+		return v, fmt.Errorf("can't get %s of nil", name)
+	}
+	if !call {
+		w := v
+		for w.Kind() == reflect.Ptr && !w.IsNil() {
+			w = w.Elem()
+		}
+		switch w.Kind() {
+		case reflect.Struct:
+			if f, ok := w.Type().FieldByName(name); ok && f.PkgPath == "" {
+				return w.FieldByIndex(f.Index), nil
+			} else if ok {
+				return v, fmt.Errorf("can't get unexported field %s of %s", name, w.Type())
+			}
+		case reflect.Map:
+			if w.Type().Key().Kind() == reflect.String {
+				if e := w.MapIndex(reflect.ValueOf(name).Convert(w.Type().Key())); e.IsValid() {
+					return e, nil
+				}
+			}
+		}
+	}
+	m := v.MethodByName(name)
+	if !m.IsValid() && v.Kind() != reflect.Ptr && v.CanAddr() {
+		m = v.Addr().MethodByName(name)
+	}
+	if !m.IsValid() {
+		return v, missingField{name, v.Type()}
+	}
+	if m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return v, fmt.Errorf("method %s of %s needs no arguments and one return value", name, v.Type())
+	}
+	return m.Call(nil)[0], nil
+}
+
+//  The reflect.Value of a Spec value with fields.
+func fieldsOf(name string, x interface{}) (v reflect.Value, err error) {
+	if fn, ok := x.(FnCall); ok && (fn.panicv != nil || len(fn.out) == 0) {
+		return v, fnerror{fn}
+	}
+	v = reflect.ValueOf(valueOfSpecValue(x))
+	if !v.IsValid() {
+		err = fmt.Errorf("%s needs a value with fields, not nil", name)
+	}
+	return
+}
+
+type haveField struct {
+	err error
+	why string
+}
+
+func (h *haveField) Matches(args []interface{}) (pass bool, err error) {
+	h.err, h.why = nil, ""
+	if len(args) < 3 {
+		return false, errors.New("HaveField needs a path and a Matcher or value")
+	}
+	path, ok := args[1].(string)
+	if !ok {
+		return false, fmt.Errorf("HaveField needs a string path, not %T", args[1])
+	}
+	v, err := fieldsOf("HaveField", args[0])
+	if err != nil {
+		h.err = err
+		return false, nil
+	}
+	field, err := walkField(v, path)
+	if err != nil {
+		h.err = fmt.Errorf("HaveField %s: %v", path, err)
+		return false, nil
+	}
+	m, margs := Equal, args[2:]
+	if inner, ok := args[2].(Matcher); ok {
+		m, margs = inner, args[3:]
+	}
+	if !acceptsNumIn(m, len(margs)+1) {
+		return false, fmt.Errorf("HaveField can't give %s %d arguments", m, len(margs))
+	}
+	pass, why, err := runMatcher(m, append([]interface{}{field.Interface()}, margs...))
+	if err != nil {
+		h.err = fmt.Errorf("HaveField %s: %v", path, err)
+	} else if !pass {
+		h.why = fmt.Sprintf("%s is %s\n%s", path, formatValue(field), nested(m, why))
+	}
+	return
+}
+
+func (h *haveField) String() string                    { return "HaveField" }
+func (h *haveField) Error() error                      { return h.err }
+func (h *haveField) clone() Matcher                    { return new(haveField) }
+func (h *haveField) NumIn() int                        { return 2 }
+func (h *haveField) IsVariadic() bool                  { return true }
+func (h *haveField) explain(args []interface{}) string { return h.why }
+
+type matchFields struct {
+	fields     map[string]Matcher
+	strictness Strictness
+	err        error
+	why        string
+}
+
+func (mf *matchFields) Matches(args []interface{}) (pass bool, err error) {
+	mf.err, mf.why = nil, ""
+	if len(args) != 1 {
+		return false, errors.New("wrong number of arguments")
+	}
+	v, err := fieldsOf("MatchFields", args[0])
+	if err != nil {
+		mf.err = err
+		return false, nil
+	}
+	var paths []string
+	for path := range mf.fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var whys []string
+	for _, path := range paths {
+		m := mf.fields[path]
+		if !acceptsNumIn(m, 1) {
+			return false, fmt.Errorf("MatchFields can't give %s no arguments; use Bind", m)
+		}
+		field, err := walkField(v, path)
+		if _, ok := err.(missingField); ok {
+			if mf.strictness&IgnoreMissing == 0 {
+				whys = append(whys, fmt.Sprintf("%s: %v", path, err))
+			}
+			continue
+		} else if err != nil {
+			mf.err = fmt.Errorf("MatchFields %s: %v", path, err)
+			return false, nil
+		}
+		pass, why, err := runMatcher(m, []interface{}{field.Interface()})
+		if err != nil {
+			mf.err = fmt.Errorf("MatchFields %s: %v", path, err)
+			return false, nil
+		}
+		if !pass {
+			whys = append(whys, fmt.Sprintf("%s: %s", path, nested(m, why)))
+		}
+	}
+	if mf.strictness&IgnoreExtras == 0 {
+		whys = append(whys, mf.extras(v)...)
+	}
+	mf.why = strings.Join(whys, "\n")
+	return len(whys) == 0, nil
+}
+
+//  Describe the exported fields of struct v without a Matcher.
+func (mf *matchFields) extras(v reflect.Value) (whys []string) {
+	v = unwrapInterface(v)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	matched := make(map[string]bool)
+	for path := range mf.fields {
+		matched[strings.SplitN(path, ".", 2)[0]] = true
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Type().Field(i); f.PkgPath == "" && !matched[f.Name] {
+			whys = append(whys, fmt.Sprintf("%s: no Matcher for %s", f.Name, formatValue(v.Field(i))))
+		}
+	}
+	return
+}
+
+func (mf *matchFields) String() string {
+	var paths []string
+	for path := range mf.fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return fmt.Sprintf("MatchFields(%s)", strings.Join(paths, ", "))
+}
+func (mf *matchFields) Error() error { return mf.err }
+func (mf *matchFields) clone() Matcher {
+	fields := make(map[string]Matcher, len(mf.fields))
+	for path, m := range mf.fields {
+		fields[path] = cloneMatcher(m)
+	}
+	return &matchFields{fields: fields, strictness: mf.strictness}
+}
+func (mf *matchFields) NumIn() int                        { return 1 }
+func (mf *matchFields) explain(args []interface{}) string { return mf.why }
@@ -0,0 +1,104 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    fields_test.go
+ *  Description: For testing fields.go
+ */
+
+import (
+	"testing"
+)
+
+type address struct {
+	City string
+}
+
+type user struct {
+	Name    string
+	Age     int
+	Address *address
+	Env     map[string]string
+	secret  string
+}
+
+func (u user) Greeting() string { return "Hello, " + u.Name }
+func (u *user) Older() int      { return u.Age + 1 }
+
+func TestHaveField(T *testing.T) {
+	u := user{Name: "gopher", Age: 2, Address: &address{"Paris"}, Env: map[string]string{"HOME": "/root"}}
+	describePassing(T, "HaveField", func(s *SpecTest) {
+		s.It("walks fields, keys and methods", func() {
+			s.Spec(u, Should, HaveField, "Name", "gopher")
+			s.Spec(&u, Should, HaveField, "Address.City", Equal, "Paris")
+			s.Spec(u, Should, HaveField, "Env.HOME", HavePrefix, "/")
+			s.Spec(u, Should, HaveField, "Greeting()", ContainSubstring, "gopher")
+			s.Spec(&u, Should, HaveField, "Older", BeBetween, 3, 3)
+			s.Spec(func() user { return u }, Should, HaveField, "Age", Negate(Equal), 3)
+		})
+	})
+}
+
+func TestHaveFieldFailures(T *testing.T) {
+	u := user{Name: "gopher", Address: &address{"Lyon"}}
+	root := runDescribed("HaveField", func(s *SpecTest) {
+		s.It("explains failures", func() {
+			s.Spec(u, Should, HaveField, "Address.City", Equal, "Paris")
+		})
+		s.It("reports errors", func() {
+			s.Spec(u, Should, HaveField, "Nope", 1)
+			s.Spec(u, Should, HaveField, "secret", "")
+			s.Spec(user{}, Should, HaveField, "Address.City", "")
+			s.Spec(u, Should, HaveField, 1, "")
+			s.Spec(u, Should, HaveField, "Name", Equal)
+			s.Spec(nil, Should, HaveField, "Name", "")
+		})
+	})
+	expectResults(T, root.Children[0], Failed, "Address.City is \"Lyon\"\nEqual failed\n\t\"Lyon\" != \"Paris\"")
+	expectResults(T, root.Children[1], Errored,
+		"HaveField Nope: no field, key or method Nope in spec.user",
+		"HaveField secret: can't get unexported field secret of spec.user",
+		"HaveField Address.City: can't get City of nil",
+		"HaveField needs a string path, not int",
+		"HaveField can't give Equal 0 arguments",
+		"HaveField needs a value with fields, not nil")
+}
+
+func TestMatchFields(T *testing.T) {
+	u := user{Name: "gopher", Age: 2, Address: &address{"Paris"}}
+	root := runDescribed("MatchFields", func(s *SpecTest) {
+		s.It("matches", func() {
+			s.Spec(u, Should, MatchFields(map[string]Matcher{
+				"Name":         Bind(Equal, "gopher"),
+				"Age":          Bind(BeBetween, 1, 3),
+				"Address.City": Bind(HavePrefix, "P"),
+			}, IgnoreExtras))
+			s.Spec(u, Should, MatchFields(map[string]Matcher{
+				"Name":  Bind(Equal, "gopher"),
+				"Other": Bind(Equal, 1),
+			}, IgnoreExtras|IgnoreMissing))
+		})
+		s.It("lists mismatched fields", func() {
+			s.Spec(u, Should, MatchFields(map[string]Matcher{
+				"Name":  Bind(Equal, "gophers"),
+				"Age":   Bind(Equal, 2),
+				"Other": BeEmpty,
+			}, Strictly))
+		})
+		s.It("reports errors", func() {
+			s.Spec(u, Should, MatchFields(map[string]Matcher{"Name": Equal}, IgnoreExtras))
+		})
+	})
+	expectResults(T, root.Children[0], Passed, "", "")
+	expect := "Name: Equal \"gophers\" failed\n\t\"gopher\" != \"gophers\"\n" +
+		"Other: no field, key or method Other in spec.user\n" +
+		"Address: no Matcher for &spec.address{City:\"Paris\"}\n" +
+		"Env: no Matcher for map[string]string(nil)"
+	expectResults(T, root.Children[1], Failed, expect)
+	expectResults(T, root.Children[2], Errored, "MatchFields can't give Equal no arguments; use Bind")
+	if IgnoreExtras != 1 || IgnoreMissing != 2 {
+		T.Errorf("unexpected strictness flags %d and %d", IgnoreExtras, IgnoreMissing)
+	}
+}
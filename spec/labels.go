@@ -0,0 +1,245 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    labels.go
+ *  Description: Label blocks and select them with label expressions.
+ */
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//  Declares blocks with labels. A Config selects labeled blocks with a
+//  label expression. See SpecTest.Labels.
+type Labeled struct {
+	t      *SpecTest
+	labels []string
+}
+
+//  Label the next block declared. Nested blocks have the labels of their
+//  ancestors.
+//      s.Labels("slow", "db").It("migrates the schema", func() { ... })
+func (t *SpecTest) Labels(labels ...string) Labeled {
+	return Labeled{t, labels}
+}
+
+//  Begin a labeled Describe block. See SpecTest.Describe.
+func (l Labeled) Describe(thing string, does func()) {
+	if h, ok := l.t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	l.t.describe(&Node{Text: thing, Labels: l.labels}, does, 1)
+}
+
+//  Begin a labeled It block. See SpecTest.It.
+func (l Labeled) It(specification string, check ...func()) {
+	if h, ok := l.t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	l.t.it(&Node{Text: specification, Labels: l.labels}, check, 1)
+}
+
+//  A synonymn of It.
+func (l Labeled) They(specification string, check ...func()) {
+	if h, ok := l.t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	l.t.it(&Node{Text: specification, Labels: l.labels}, check, 1)
+}
+
+//  Declare a labeled It block that is not implemented yet. See SpecTest.PIt.
+func (l Labeled) PIt(specification string, check ...func()) {
+	if h, ok := l.t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	l.t.it(&Node{Text: specification, Labels: l.labels}, nil, 1)
+}
+
+//  A synonymn of PIt.
+func (l Labeled) XIt(specification string, check ...func()) {
+	if h, ok := l.t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	l.t.it(&Node{Text: specification, Labels: l.labels}, nil, 1)
+}
+
+//  Declare a labeled Describe block that is not implemented yet. See
+//  SpecTest.PDescribe.
+func (l Labeled) PDescribe(thing string, does func()) {
+	if h, ok := l.t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	l.t.describe(&Node{Text: thing, Labels: l.labels, Pending: true}, does, 1)
+}
+
+//  A synonymn of PDescribe.
+func (l Labeled) XDescribe(thing string, does func()) {
+	if h, ok := l.t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	l.t.describe(&Node{Text: thing, Labels: l.labels, Pending: true}, does, 1)
+}
+
+//  Declare a labeled focused Describe block. See SpecTest.FDescribe.
+func (l Labeled) FDescribe(thing string, does func()) {
+	if h, ok := l.t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	l.t.describe(&Node{Text: thing, Labels: l.labels, Focused: true}, does, 1)
+}
+
+//  Declare a labeled focused It block. See SpecTest.FIt.
+func (l Labeled) FIt(specification string, check ...func()) {
+	if h, ok := l.t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	l.t.it(&Node{Text: specification, Labels: l.labels, Focused: true}, check, 1)
+}
+
+//  A synonymn of FIt.
+func (l Labeled) FThey(specification string, check ...func()) {
+	if h, ok := l.t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	l.t.it(&Node{Text: specification, Labels: l.labels, Focused: true}, check, 1)
+}
+
+//  The labels of n and its ancestors.
+func (n *Node) labelSet() map[string]bool {
+	set := make(map[string]bool)
+	for ; n != nil; n = n.Parent {
+		for _, label := range n.Labels {
+			set[label] = true
+		}
+	}
+	return set
+}
+
+//  A compiled label expression. Returns true if a set of labels is selected.
+type labelExpr func(labels map[string]bool) bool
+
+//  Compile a label expression like "integration && !slow". Expressions are
+//  labels combined with the operators "!", "&&" and "||" (in order of
+//  precedence) and grouped with parentheses.
+func compileLabels(expr string) (labelExpr, error) {
+	p := &labelParser{expr: expr}
+	e, err := p.or()
+	if err == nil && p.peek() != "" {
+		err = p.errorf("unexpected %q", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Can't compile label expression %q: %v", expr, err)
+	}
+	return e, nil
+}
+
+//  A recursive descent parser of label expressions.
+type labelParser struct {
+	expr string
+	pos  int // The offset of the next token.
+}
+
+func (p *labelParser) errorf(format string, v ...interface{}) error {
+	return fmt.Errorf("offset %d: %s", p.pos, fmt.Sprintf(format, v...))
+}
+
+//  The next token, which is an operator, a parenthesis, a label, or "" at
+//  the end of the expression.
+func (p *labelParser) peek() string {
+	for p.pos < len(p.expr) && (p.expr[p.pos] == ' ' || p.expr[p.pos] == '\t') {
+		p.pos++
+	}
+	s := p.expr[p.pos:]
+	switch {
+	case s == "":
+		return ""
+	case strings.HasPrefix(s, "&&"), strings.HasPrefix(s, "||"):
+		return s[:2]
+	case strings.ContainsAny(s[:1], "!()"):
+		return s[:1]
+	}
+	switch i := strings.IndexFunc(s, func(r rune) bool { return !isLabelRune(r) }); {
+	case i < 0:
+		return s
+	case i == 0:
+		// A character that can't start any token.
+		_, n := utf8.DecodeRuneInString(s)
+		return s[:n]
+	default:
+		return s[:i]
+	}
+}
+
+//  Returns true if r can be part of a label.
+func isLabelRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.:/", r)
+}
+
+func (p *labelParser) next() string {
+	tok := p.peek()
+	p.pos += len(tok)
+	return tok
+}
+
+func (p *labelParser) or() (labelExpr, error) {
+	x, err := p.and()
+	for err == nil && p.peek() == "||" {
+		p.next()
+		var y labelExpr
+		if y, err = p.and(); err == nil {
+			a, b := x, y
+			x = func(labels map[string]bool) bool { return a(labels) || b(labels) }
+		}
+	}
+	return x, err
+}
+
+func (p *labelParser) and() (labelExpr, error) {
+	x, err := p.not()
+	for err == nil && p.peek() == "&&" {
+		p.next()
+		var y labelExpr
+		if y, err = p.not(); err == nil {
+			a, b := x, y
+			x = func(labels map[string]bool) bool { return a(labels) && b(labels) }
+		}
+	}
+	return x, err
+}
+
+func (p *labelParser) not() (labelExpr, error) {
+	switch tok := p.peek(); tok {
+	case "!":
+		p.next()
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return func(labels map[string]bool) bool { return !x(labels) }, nil
+	case "(":
+		p.next()
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, p.errorf("missing )")
+		}
+		p.next()
+		return x, nil
+	case "", ")", "&&", "||":
+		return nil, p.errorf("missing label")
+	default:
+		if r, _ := utf8.DecodeRuneInString(tok); !isLabelRune(r) {
+			return nil, p.errorf("unexpected %q", tok)
+		}
+		p.next()
+		return func(labels map[string]bool) bool { return labels[tok] }, nil
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    labels_test.go
+ *  Description: For testing labels.go
+ */
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompileLabels(T *testing.T) {
+	labels := map[string]bool{"integration": true, "db": true}
+	for expr, expect := range map[string]bool{
+		"integration":                  true,
+		"slow":                         false,
+		"!slow":                        true,
+		"integration && !slow":         true,
+		"integration && slow":          false,
+		"slow || db":                   true,
+		"slow || integration && db":    true,
+		"(slow || integration) && !db": false,
+		"!(slow || db)":                false,
+		" !!db ":                       true,
+	} {
+		e, err := compileLabels(expr)
+		if err != nil {
+			T.Errorf("%q: %v", expr, err)
+		} else if e(labels) != expect {
+			T.Errorf("%q: selected %v", expr, !expect)
+		}
+	}
+	for expr, msg := range map[string]string{
+		"":            "offset 0: missing label",
+		"a &&":        "offset 4: missing label",
+		"a b":         `offset 2: unexpected "b"`,
+		"(a || b":     "offset 7: missing )",
+		"a & b":       `offset 2: unexpected "&"`,
+		"a && ) || b": "offset 5: missing label",
+	} {
+		_, err := compileLabels(expr)
+		if err == nil || !strings.HasSuffix(err.Error(), msg) {
+			T.Errorf("%q: unexpected error %v", expr, err)
+		}
+	}
+}
+
+func TestLabels(T *testing.T) {
+	run := func(labels string) (ran []string, mock *mockTest) {
+		mock = new(mockTest)
+		s := NewSpecTestConfig(mock, Config{Labels: labels})
+		mark := marker(&ran)
+		s.Describe("labels", func() {
+			s.It("a", mark("a"))
+			s.Labels("slow").It("b", mark("b"))
+			s.Labels("integration").Describe("c", func() {
+				s.It("d", mark("d"))
+				s.Labels("slow").They("e", mark("e"))
+			})
+		})
+		return
+	}
+	for labels, expect := range map[string]string{
+		"":                     "a b d e",
+		"slow":                 "b e",
+		"!slow":                "a d",
+		"integration && !slow": "d",
+		"integration || slow":  "b d e",
+	} {
+		ran, mock := run(labels)
+		if s := strings.Join(ran, " "); s != expect || len(mock.errors) != 0 {
+			T.Errorf("%q: ran %q with errors %q", labels, s, mock.errors)
+		}
+	}
+
+	// Pending and focused blocks keep their labels.
+	mock := new(mockTest)
+	s := NewSpecTestConfig(mock, Config{Labels: "slow"})
+	var ran []string
+	mark := marker(&ran)
+	root := s.Collect("labels", func() {
+		s.Labels("slow").PIt("a", mark("a"))
+		s.Labels("slow").XIt("b", mark("b"))
+		s.Labels("slow").PDescribe("c", func() { s.It("d", mark("d")) })
+		s.Labels("slow").XDescribe("e", func() { s.It("f", mark("f")) })
+		s.Labels("slow").FDescribe("g", func() { s.It("h", mark("h")) })
+		s.Labels("slow").FIt("i", mark("i"))
+		s.Labels("slow").FThey("j", mark("j"))
+		s.Labels("fast").FIt("k", mark("k"))
+	})
+	s.Run(root)
+	if s := strings.Join(ran, " "); s != "h i j" || len(mock.errors) != 1 {
+		T.Errorf("ran %q with errors %q", s, mock.errors)
+	}
+	var leaves []string
+	root.Walk(func(n *Node) bool {
+		if n.Kind == NodeLeaf {
+			labels := n.labelSet()
+			leaves = append(leaves, fmt.Sprintf("%s %v %v %v", n.Text, labels["slow"], n.pending(), n.focused()))
+		}
+		if !strings.HasPrefix(n.Location.String(), "labels_test.go:") {
+			T.Errorf("unexpected location %s of %s", n.Location, n)
+		}
+		return true
+	})
+	expect := "a true true false, b true true false, d true true false, f true true false, " +
+		"h true false true, i true false true, j true false true, k false false true"
+	if s := strings.Join(leaves, ", "); s != expect {
+		T.Errorf("leaves %q", s)
+	}
+
+	ran, mock = run("slow &&")
+	if len(ran) != 0 || len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "Can't compile label expression") {
+		T.Errorf("ran %q with errors %q", ran, mock.errors)
+	}
+}
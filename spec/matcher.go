@@ -14,6 +14,7 @@ import (
 	"reflect"
 	"errors"
 	"fmt"
+	"runtime/debug"
 )
 
 var boolval = true
@@ -23,17 +24,21 @@ var errorType = reflect.TypeOf(errorval)
 
 //  The default set of Spec matchers.
 var (
-	Equal     = MatcherMust(NewMatcher("Equal", matcherEqual))
+	Equal     = explained(MatcherMust(NewMatcher("Equal", matcherEqual)), explainEqual)
 	Satisfy   = MatcherMust(NewMatcher("Satisfy", matcherSatisfy))
 	HaveError = MatcherMust(NewMatcher("HaveError", matcherHaveError))
-	Panic     = MatcherMust(NewMatcher("Panic", matcherPanic))
+	Panic     = explainedPanic(MatcherMust(NewMatcher("Panic", matcherPanic)))
 )
 
 //  If x is not a FnCall, return x. Otherwise, return the first return value
-//  of x.
+//  of x. If x has no value (it panicked or returns nothing) a fnerror is
+//  raised for the matcher to report.
 func valueOfSpecValue(x interface{}) (y interface{}) {
 	switch x.(type) {
 	case FnCall:
+		if fn := x.(FnCall); fn.panicv != nil || len(fn.out) == 0 {
+			panic(fnerror{fn})
+		}
 		y = x.(FnCall).out[0].Interface()
 	default:
 		y = x
@@ -50,6 +55,11 @@ func matcherEqual(a, b interface{}) (pass bool, err error) {
 	return
 }
 
+//  Describe the differences between the values compared by Equal.
+func explainEqual(args []interface{}) string {
+	return diffValues(valueOfSpecValue(args[0]), valueOfSpecValue(args[1]))
+}
+
 func matcherSatisfy(x, fn interface{}) (pass bool, err error) {
 	//t.doDebug(func() { t.Logf("%#v satisfies function %#v", x, fn) })
 	fnval := reflect.ValueOf(fn)
@@ -91,6 +101,9 @@ func matcherHaveError(fn interface{}) (pass bool, err error) {
 			// error: fn's last return value error.
 			return false, errors.New("HaveError function call's last Value must be os.Error")
 		}
+		if fncall.panicv != nil {
+			return false, fnerror{fncall}
+		}
 		errval = fncall.out[len(fncall.out)-1]
 	default:
 		err = errors.New("HaveError needs a function call Value")
@@ -137,27 +150,108 @@ type Matcher interface {
 	NumIn() int
 }
 
+//  A Matcher that accepts any number of arguments after the first NumIn.
+type VariadicMatcher interface {
+	Matcher
+	IsVariadic() bool
+}
+
+//  Returns true if m accepts n arguments.
+func acceptsNumIn(m Matcher, n int) bool {
+	if v, ok := m.(VariadicMatcher); ok && v.IsVariadic() {
+		return n >= m.NumIn()
+	}
+	return n == m.NumIn()
+}
+
+//  Implemented by Matchers that keep the state of a call of Matches, like
+//  its error or the explanation of a mismatch. Specs match with clones of
+//  such Matchers, so that tests running in parallel can share them.
+type cloner interface {
+	clone() Matcher
+}
+
+//  A clone of m without the state of previous calls, which clones the
+//  Matchers m is made of.
+func cloneMatcher(m Matcher) Matcher {
+	if c, ok := m.(cloner); ok {
+		return c.clone()
+	}
+	return m
+}
+
+//  Clone the Matchers given as arguments.
+func cloneArgs(args []interface{}) []interface{} {
+	clones := make([]interface{}, len(args))
+	for i, arg := range args {
+		if m, ok := arg.(Matcher); ok {
+			arg = cloneMatcher(m)
+		}
+		clones[i] = arg
+	}
+	return clones
+}
+
+//  Implemented by Matchers that can explain why arguments did not match.
+type explainer interface {
+	explain(args []interface{}) string
+}
+
+//  Implemented by Matchers that can explain why arguments matched, when a
+//  negated Spec fails.
+type negatedExplainer interface {
+	explainNegated(args []interface{}) string
+}
+
 type match struct {
-	name string        // For printing purposes
-	fn   reflect.Value // A bool function of at least one argument
-	typ  reflect.Type  // A type with kind reflect.Func
-	err  error         // An error encountered when running err (panic / bug)
+	name   string                          // For printing purposes
+	fn     reflect.Value                   // A bool function of at least one argument
+	typ    reflect.Type                    // A type with kind reflect.Func
+	err    error                           // An error encountered when running err (panic / bug)
+	why    func(args []interface{}) string // Explains a failed match (optional)
+	whyNot func(args []interface{}) string // Explains a failed negated match (optional)
 }
 
+//  A recovered panic.
 type errpanic struct {
-	v interface{}
+	v     interface{}
+	stack []byte   // The stack trace of the panic
+	loc   Location // Where the panic happened
+}
+
+//  Describe a value recovered from a panic. Must be called by the deferred
+//  function that recovered it.
+func recovered(v interface{}) errpanic {
+	return errpanic{v, debug.Stack(), panicLocation()}
 }
 
 func (ep errpanic) Error() string {
-	return fmt.Sprintf("runtime panic: %v", ep.v)
+	if ep.stack == nil {
+		return fmt.Sprintf("runtime panic: %v", ep.v)
+	}
+	return fmt.Sprintf("runtime panic: %v\n%s", ep.v, ep.stack)
+}
+
+//  Raised when a matcher needs the value of a function call without one.
+type fnerror struct {
+	fn FnCall
+}
+
+func (fe fnerror) Error() string {
+	if fe.fn.panicv == nil {
+		return "Value-less function"
+	}
+	return fmt.Sprintf("function call panicked: %v\n%s", fe.fn.panicv, fe.fn.stack)
 }
 
 func (m *match) call(args []reflect.Value) (pass bool, err error) {
 	defer func() {
 		if e := recover(); e != nil {
-			m.err = errpanic{e}
-            err = m.err
-            panic(e)
+			if fe, ok := e.(fnerror); ok {
+				m.err = fe
+				return
+			}
+			m.err = recovered(e)
 		}
 	}()
 	out := m.fn.Call(args)
@@ -173,24 +267,90 @@ func (m *match) call(args []reflect.Value) (pass bool, err error) {
 }
 
 func (m *match) Matches(args []interface{}) (bool, error) {
+	m.err = nil
 	n := len(args)
 	// Check the arguments.
-	if n != m.NumIn() {
+	if !acceptsNumIn(m, n) {
 		return false, errors.New("wrong number of arguments")
 	}
 	// Turn interfaces into reflect.Values and call the matcher.
 	vals := make([]reflect.Value, n)
 	for i := range args {
+		typ := m.inType(i)
 		vals[i] = reflect.ValueOf(args[i])
+		if !vals[i].IsValid() {
+			// A nil argument.
+			vals[i] = reflect.Zero(typ)
+		} else if fn, ok := args[i].(FnCall); ok && !vals[i].Type().AssignableTo(typ) {
+			// Typed matcher functions get the function call's value.
+			if fn.panicv != nil || len(fn.out) == 0 {
+				m.err = fnerror{fn}
+				return false, nil
+			}
+			vals[i] = reflect.ValueOf(valueOfSpecValue(args[i]))
+		}
 	}
 	return m.call(vals)
 }
+
+//  The type of the matcher function's argument i.
+func (m *match) inType(i int) reflect.Type {
+	if m.IsVariadic() && i >= m.NumIn() {
+		return m.typ.In(m.NumIn()).Elem()
+	}
+	return m.typ.In(i)
+}
 func (m *match) String() string { return m.name }
 func (m *match) Error() error   { return m.err }
-func (m *match) NumIn() int     { return m.typ.NumIn() }
+func (m *match) clone() Matcher {
+	c := *m
+	c.err = nil
+	return &c
+}
+func (m *match) IsVariadic() bool { return m.typ.IsVariadic() }
+func (m *match) NumIn() int {
+	if m.typ.IsVariadic() {
+		return m.typ.NumIn() - 1
+	}
+	return m.typ.NumIn()
+}
+func (m *match) FailureMessage(args []interface{}) string {
+	return expectation(m.name, false, args)
+}
+func (m *match) NegatedFailureMessage(args []interface{}) string {
+	return expectation(m.name, true, args)
+}
+func (m *match) explain(args []interface{}) string {
+	if m.why == nil {
+		return ""
+	}
+	return m.why(args)
+}
+func (m *match) explainNegated(args []interface{}) string {
+	if m.whyNot == nil {
+		return ""
+	}
+	return m.whyNot(args)
+}
+
+//  Attach a function explaining failed matches to a Matcher created by
+//  NewMatcher.
+func explained(m Matcher, why func(args []interface{}) string) Matcher {
+	m.(*match).why = why
+	return m
+}
 
 //  Create a new Matcher object from function fn. Function fn must take
-//  at least one argument and return exactly one bool.
+//  at least one argument and return a bool and an error. When fn is variadic
+//  it must take at least one argument before the variadic arguments, and the
+//  Matcher is a VariadicMatcher.
+//      Sum, err := NewMatcher("Sum", func(x int, terms ...int) (bool, error) {
+//          for _, t := range terms {
+//              x -= t
+//          }
+//          return x == 0, nil
+//      })
+//      s.Spec(6, Should, Sum, 1, 2, 3)
 func NewMatcher(name string, fn interface{}) (Matcher, error) {
 	m := new(match)
 	m.name = name
@@ -205,6 +365,8 @@ func NewMatcher(name string, fn interface{}) (Matcher, error) {
 	// Check the number of inputs on fn
 	if numin := m.typ.NumIn(); numin == 0 {
 		return m, errors.New("nil-adic matcher")
+	} else if numin == 1 && m.typ.IsVariadic() {
+		return m, errors.New("variadic matcher without a fixed argument")
 	}
 
 	// Check the number of outputs on fn
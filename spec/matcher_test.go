@@ -7,6 +7,9 @@ package spec
  */
 
 import (
+    "errors"
+    "strings"
+    "sync"
     "testing"
 )
 
@@ -15,3 +18,35 @@ func TestMatcher(T *testing.T) {
 
 }
 
+func TestMatcherClones(T *testing.T) {
+	bad := MatcherMust(NewMatcher("Bad", func(x int) (bool, error) { return false, errors.New("bad") }))
+	var wg sync.WaitGroup
+	mocks := make([]*mockTest, 4)
+	for i := range mocks {
+		mocks[i] = new(mockTest)
+		wg.Add(1)
+		go func(s *SpecTest) {
+			defer wg.Done()
+			ch := make(chan int, 1)
+			ch <- 1
+			s.Describe("Shared matchers", func() {
+				s.It("match in parallel", func() {
+					s.Spec(1, Should, Equal, 1)
+					s.Spec(1, Should, bad)
+					s.Spec(user{Name: "gopher"}, Should, HaveField, "Name", Equal, "gopher")
+					s.Spec(ch, Should, Receive, Equal, 1)
+					s.Spec(1, Should, AllOf(Equal, bad), 1)
+				})
+			})
+		}(NewSpecTestConfig(mocks[i], Config{}))
+	}
+	wg.Wait()
+	for _, mock := range mocks {
+		if len(mock.errors) != 1 || strings.Count(mock.errors[0], "Error: ") != 2 {
+			T.Errorf("unexpected errors %q", mock.errors)
+		}
+	}
+	if bad.Error() != nil || Equal.Error() != nil || HaveField.Error() != nil {
+		T.Error("Specs changed the state of shared Matchers")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    message.go
+ *  Description: Sentences describing failed Specs.
+ */
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+//  A Matcher that describes its failures in a sentence, like
+//  "expected [1 2] to contain 3". Matchers created by NewMatcher describe
+//  failures using their name. Other Matchers can implement FailureMessager
+//  to do the same.
+type FailureMessager interface {
+	Matcher
+	// Describe why args did not match.
+	FailureMessage(args []interface{}) string
+	// Describe why args matched when they should not have.
+	NegatedFailureMessage(args []interface{}) string
+}
+
+//  Describe the failure of a Spec with Matcher m.
+func failureMessage(m Matcher, negated bool, args []interface{}) string {
+	if f, ok := m.(FailureMessager); ok {
+		if negated {
+			return f.NegatedFailureMessage(args)
+		}
+		return f.FailureMessage(args)
+	}
+	return expectation(m.String(), negated, args)
+}
+
+//  A sentence expecting args to match the Matcher with the given name.
+//      expected [1 2] to contain 3
+//      expected 4 not to be between 1, 5
+func expectation(name string, negated bool, args []interface{}) string {
+	to := "to"
+	if negated {
+		to = "not to"
+	}
+	s := fmt.Sprintf("expected %s %s %s", formatSpecValue(args[0]), to, humanize(name))
+	if len(args) > 1 {
+		s += " " + formatArgs(args[1:])
+	}
+	return s
+}
+
+//  Format Matcher arguments for a failure message.
+func formatArgs(args []interface{}) string {
+	vals := make([]string, len(args))
+	for i := range args {
+		vals[i] = formatSpecValue(args[i])
+	}
+	return strings.Join(vals, ", ")
+}
+
+//  Turn a Matcher name like "BeGreaterThan" into words like "be greater than".
+//  Names that aren't identifiers, like "AllOf(Equal, Contain)", become
+//  "match AllOf(Equal, Contain)".
+func humanize(name string) string {
+	var words []string
+	start := 0
+	for i, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return "match " + name
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, strings.ToLower(name[start:i]))
+			start = i
+		}
+	}
+	return strings.Join(append(words, strings.ToLower(name[start:])), " ")
+}
+
+//  Format a Spec value for a failure message. Function calls show their
+//  return values, or their panic.
+func formatSpecValue(x interface{}) string {
+	switch v := x.(type) {
+	case FnCall:
+		switch {
+		case v.panicv != nil:
+			return fmt.Sprintf("%s (panicked with %v)", v.fn.Type(), v.panicv)
+		case len(v.out) == 0:
+			return v.fn.Type().String()
+		case len(v.out) == 1:
+			return formatSpecValue(v.out[0].Interface())
+		}
+		outs := make([]string, len(v.out))
+		for i := range v.out {
+			outs[i] = formatSpecValue(v.out[i].Interface())
+		}
+		return "(" + strings.Join(outs, ", ") + ")"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case nil:
+		return "nil"
+	}
+	return fmt.Sprintf("%v", x)
+}
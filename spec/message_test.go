@@ -0,0 +1,64 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    message_test.go
+ *  Description: For testing message.go
+ */
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type loudMatcher struct {
+	Matcher
+}
+
+func (m loudMatcher) FailureMessage(args []interface{}) string {
+	return fmt.Sprintf("%v IS NOT %v", args[0], args[1])
+}
+func (m loudMatcher) NegatedFailureMessage(args []interface{}) string {
+	return fmt.Sprintf("%v IS %v", args[0], args[1])
+}
+
+func TestFailureMessages(T *testing.T) {
+	mock := new(mockTest)
+	s := NewSpecTestConfig(mock, Config{})
+	root := s.Collect("Failure messages", func() {
+		s.It("describe failures", func() {
+			s.Spec([]int{1, 2}, Should, Contain, 3)
+			s.Spec(4, Should, Not, BeBetween, 1, 5)
+			s.Spec("abc", Should, HavePrefix, "b")
+			s.Spec(func() (int, error) { return 1, nil }, Should, HaveError)
+			s.Spec(15, Should, AllOf(BeGreaterThan, BeLessThan), 0, 10)
+			s.Spec(1, Should, loudMatcher{Equal}, 2)
+			s.Spec(1, Should, Not, loudMatcher{Equal}, 1)
+		})
+	})
+	s.Run(root)
+	results := root.Children[0].Results
+	expect := []string{
+		"expected [1 2] to contain 3",
+		"expected 4 not to be between 1, 5",
+		`expected "abc" to have prefix "b"`,
+		"expected (1, nil) to have error",
+		"expected 15 to match AllOf(BeGreaterThan, BeLessThan) 0, 10",
+		"1 IS NOT 2",
+		"1 IS 1",
+	}
+	if len(results) != len(expect) {
+		T.Fatalf("%d results; expected %d", len(results), len(expect))
+	}
+	for i, r := range results {
+		if r.Message != expect[i] {
+			T.Errorf("unexpected message %q", r.Message)
+		}
+	}
+	if !strings.Contains(mock.errors[0], "FAIL #1: []int{1, 2} Should Contain 3\n\t\texpected [1 2] to contain 3") {
+		T.Errorf("unexpected report %q", mock.errors[0])
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    numeric.go
+ *  Description: Matchers comparing integer, floating point and complex numbers.
+ */
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/cmplx"
+	"reflect"
+)
+
+//  Matchers for numbers of any integer, floating point, or complex kind.
+//  Numbers of different kinds are compared by value.
+//      s.Spec(x, Should, BeGreaterThan, 3)
+//      s.Spec(uint8(4), Should, BeLessThan, 4.5)
+//      s.Spec(x, Should, BeBetween, 1, 10)
+//      s.Spec(math.Pi, Should, BeWithin, 3.14, 0.01)
+//      s.Spec(math.NaN(), Should, BeNaN)
+//      s.Spec(math.Inf(-1), Should, BeInf)
+//  BeBetween includes its bounds. BeWithin checks that the distance between
+//  the object and the expected number is no more than a tolerance. Complex
+//  numbers can not be ordered, so they are only accepted by BeWithin, BeNaN,
+//  and BeInf.
+var (
+	BeGreaterThan = explained(MatcherMust(NewMatcher("BeGreaterThan", matcherBeGreaterThan)), explainOrder)
+	BeLessThan    = explained(MatcherMust(NewMatcher("BeLessThan", matcherBeLessThan)), explainOrder)
+	BeBetween     = explained(MatcherMust(NewMatcher("BeBetween", matcherBeBetween)), explainOrder)
+	BeWithin      = explained(MatcherMust(NewMatcher("BeWithin", matcherBeWithin)), explainWithin)
+	BeNaN         = MatcherMust(NewMatcher("BeNaN", matcherBeNaN))
+	BeInf         = MatcherMust(NewMatcher("BeInf", matcherBeInf))
+)
+
+//  The reflect.Value of a numeric Spec value.
+func numberOf(name string, x interface{}) (v reflect.Value, err error) {
+	v = reflect.ValueOf(valueOfSpecValue(x))
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Complex64, reflect.Complex128:
+		return
+	}
+	err = fmt.Errorf("%s needs a number, not %T", name, valueOfSpecValue(x))
+	return
+}
+
+func isComplex(v reflect.Value) bool {
+	return v.Kind() == reflect.Complex64 || v.Kind() == reflect.Complex128
+}
+
+//  The exact value of a real number. Returns nil for NaN.
+func bigOf(v reflect.Value) *big.Float {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Float).SetUint64(v.Uint())
+	}
+	if math.IsNaN(v.Float()) {
+		return nil
+	}
+	return new(big.Float).SetFloat64(v.Float())
+}
+
+//  The value of a number as a complex128.
+func complexOf(v reflect.Value) complex128 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return complex(float64(v.Int()), 0)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return complex(float64(v.Uint()), 0)
+	case reflect.Float32, reflect.Float64:
+		return complex(v.Float(), 0)
+	}
+	return v.Complex()
+}
+
+//  Compare numbers x and y like big.Float.Cmp. The comparison is false
+//  (ok is false) when either number is NaN.
+func compareNumbers(name string, x, y interface{}) (cmp int, ok bool, err error) {
+	a, err := numberOf(name, x)
+	if err != nil {
+		return
+	}
+	b, err := numberOf(name, y)
+	if err != nil {
+		return
+	}
+	if isComplex(a) || isComplex(b) {
+		err = fmt.Errorf("%s can't order complex numbers", name)
+		return
+	}
+	p, q := bigOf(a), bigOf(b)
+	if p == nil || q == nil {
+		return 0, false, nil
+	}
+	return p.Cmp(q), true, nil
+}
+
+func matcherBeGreaterThan(x, y interface{}) (pass bool, err error) {
+	cmp, ok, err := compareNumbers("BeGreaterThan", x, y)
+	return ok && cmp > 0, err
+}
+
+func matcherBeLessThan(x, y interface{}) (pass bool, err error) {
+	cmp, ok, err := compareNumbers("BeLessThan", x, y)
+	return ok && cmp < 0, err
+}
+
+func matcherBeBetween(x, lo, hi interface{}) (pass bool, err error) {
+	cmplo, oklo, err := compareNumbers("BeBetween", x, lo)
+	if err != nil {
+		return
+	}
+	cmphi, okhi, err := compareNumbers("BeBetween", x, hi)
+	return oklo && okhi && cmplo >= 0 && cmphi <= 0, err
+}
+
+func explainOrder(args []interface{}) string {
+	x := valueOfSpecValue(args[0])
+	switch len(args) {
+	case 3:
+		return fmt.Sprintf("%v is not in [%v, %v]", x, valueOfSpecValue(args[1]), valueOfSpecValue(args[2]))
+	}
+	cmp, ok, _ := compareNumbers("", args[0], args[1])
+	switch {
+	case !ok:
+		return fmt.Sprintf("%v and %v are not ordered", x, valueOfSpecValue(args[1]))
+	case cmp == 0:
+		return fmt.Sprintf("%v == %v", x, valueOfSpecValue(args[1]))
+	case cmp < 0:
+		return fmt.Sprintf("%v < %v", x, valueOfSpecValue(args[1]))
+	}
+	return fmt.Sprintf("%v > %v", x, valueOfSpecValue(args[1]))
+}
+
+//  The distance between numbers x and y.
+func distance(name string, x, y interface{}) (d float64, err error) {
+	a, err := numberOf(name, x)
+	if err != nil {
+		return
+	}
+	b, err := numberOf(name, y)
+	if err != nil {
+		return
+	}
+	if !isComplex(a) && !isComplex(b) {
+		// Subtract exactly to avoid losing precision of large integers.
+		p, q := bigOf(a), bigOf(b)
+		if p == nil || q == nil {
+			return math.NaN(), nil
+		}
+		if p.IsInf() || q.IsInf() {
+			return cmplx.Abs(complexOf(a) - complexOf(b)), nil
+		}
+		d, _ = new(big.Float).Sub(p, q).Float64()
+		return math.Abs(d), nil
+	}
+	return cmplx.Abs(complexOf(a) - complexOf(b)), nil
+}
+
+func matcherBeWithin(x, expected, tolerance interface{}) (pass bool, err error) {
+	d, err := distance("BeWithin", x, expected)
+	if err != nil {
+		return
+	}
+	tol, err := numberOf("BeWithin", tolerance)
+	if err != nil {
+		return
+	}
+	if isComplex(tol) {
+		return false, fmt.Errorf("BeWithin needs a real tolerance, not %v", tol)
+	}
+	return d <= real(complexOf(tol)), nil
+}
+
+func explainWithin(args []interface{}) string {
+	d, err := distance("BeWithin", args[0], args[1])
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%v differs from %v by %g", valueOfSpecValue(args[0]), valueOfSpecValue(args[1]), d)
+}
+
+func matcherBeNaN(x interface{}) (pass bool, err error) {
+	v, err := numberOf("BeNaN", x)
+	if err != nil {
+		return
+	}
+	return cmplx.IsNaN(complexOf(v)), nil
+}
+
+func matcherBeInf(x interface{}) (pass bool, err error) {
+	v, err := numberOf("BeInf", x)
+	if err != nil {
+		return
+	}
+	return cmplx.IsInf(complexOf(v)), nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    numeric_test.go
+ *  Description: For testing numeric.go
+ */
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNumericMatchers(T *testing.T) {
+	describePassing(T, "Numeric matchers", func(s *SpecTest) {
+		s.It("match", func() {
+			s.Spec(4, Should, BeGreaterThan, 3)
+			s.Spec(uint8(4), Should, BeLessThan, 4.5)
+			s.Spec(uint64(math.MaxUint64), Should, BeGreaterThan, int64(math.MaxInt64))
+			s.Spec(int64(-1), Should, BeLessThan, uint64(0))
+			s.Spec(int64(1<<62+1), Should, BeGreaterThan, float64(1<<62))
+			s.Spec(math.NaN(), Should, Not, BeGreaterThan, 0)
+			s.Spec(math.NaN(), Should, Not, BeLessThan, 0)
+			s.Spec(float32(2), Should, BeBetween, 1, 2)
+			s.Spec(3, Should, Not, BeBetween, 1, 2)
+			s.Spec(math.Pi, Should, BeWithin, 3.14, 0.01)
+			s.Spec(math.Pi, Should, Not, BeWithin, 3.14, 0.001)
+			s.Spec(1+1i, Should, BeWithin, 1, 1)
+			s.Spec(func() int { return 10 }, Should, BeWithin, 12, 2)
+			s.Spec(math.NaN(), Should, BeNaN)
+			s.Spec(complex(0, math.NaN()), Should, BeNaN)
+			s.Spec(1, Should, Not, BeNaN)
+			s.Spec(math.Inf(-1), Should, BeInf)
+			s.Spec(math.MaxFloat64, Should, Not, BeInf)
+		})
+	})
+}
+
+func TestNumericErrors(T *testing.T) {
+	for _, test := range []struct {
+		m    Matcher
+		args []interface{}
+	}{
+		{BeGreaterThan, []interface{}{"a", 1}},
+		{BeLessThan, []interface{}{1i, 1}},
+		{BeWithin, []interface{}{1, 1, 1i}},
+		{BeNaN, []interface{}{nil}},
+	} {
+		if _, err := test.m.Matches(test.args); err == nil && test.m.Error() == nil {
+			T.Errorf("%s accepted %#v", test.m, test.args)
+		}
+	}
+}
+
+func TestNumericExplanations(T *testing.T) {
+	for _, test := range []struct {
+		m      Matcher
+		args   []interface{}
+		expect string
+	}{
+		{BeGreaterThan, []interface{}{2, 3}, "2 < 3"},
+		{BeLessThan, []interface{}{3, 3.0}, "3 == 3"},
+		{BeBetween, []interface{}{0, 1, 2}, "0 is not in [1, 2]"},
+		{BeWithin, []interface{}{1.5, 1, 0.1}, "1.5 differs from 1 by 0.5"},
+	} {
+		if s := test.m.(explainer).explain(test.args); s != test.expect {
+			T.Errorf("%s: unexpected explanation %q", test.m, s)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    panic.go
+ *  Description: Matchers inspecting the values of function call panics.
+ */
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+//  Matchers for the value recovered from a function call that panicked.
+//      s.Spec(func() { panic("boom") }, Should, PanicWith, "boom")
+//      s.Spec(func() { panic(io.EOF) }, Should, PanicWith, io.EOF)
+//      s.Spec(func() { panic("index 3") }, Should, PanicMatching, MatchRegexp, `\d+`)
+//  PanicWith compares the panic value with Equal, or with errors.Is when
+//  both are errors. PanicMatching applies a Matcher (and any arguments that
+//  follow it) to the panic value. When they fail, negated or not, the panic
+//  value and its stack trace are shown.
+var (
+	PanicWith     = explainedPanic(MatcherMust(NewMatcher("PanicWith", matcherPanicWith)))
+	PanicMatching = explainedPanic(MatcherMust(NewMatcher("PanicMatching", matcherPanicMatching)))
+)
+
+//  Attach the explanations of panic matchers, which show the panic value and
+//  its stack trace whether or not the Spec is negated.
+func explainedPanic(m Matcher) Matcher {
+	m.(*match).whyNot = explainPanicked
+	return explained(m, explainPanic)
+}
+
+//  The function call of a panic matcher's object.
+func panicOf(name string, x interface{}) (fn FnCall, err error) {
+	fn, ok := x.(FnCall)
+	if !ok {
+		err = fmt.Errorf("%s needs a function call Value", name)
+	}
+	return
+}
+
+func matcherPanicWith(x, value interface{}) (pass bool, err error) {
+	fn, err := panicOf("PanicWith", x)
+	if err != nil || fn.panicv == nil {
+		return
+	}
+	if e, ok := fn.panicv.(error); ok {
+		if target, ok := value.(error); ok {
+			return errors.Is(e, target), nil
+		}
+	}
+	return reflect.DeepEqual(fn.panicv, value), nil
+}
+
+func matcherPanicMatching(x, m interface{}, args ...interface{}) (pass bool, err error) {
+	fn, err := panicOf("PanicMatching", x)
+	if err != nil {
+		return
+	}
+	inner, ok := m.(Matcher)
+	if !ok {
+		return false, fmt.Errorf("PanicMatching needs a Matcher, not %T", m)
+	}
+	if !acceptsNumIn(inner, len(args)+1) {
+		return false, fmt.Errorf("PanicMatching can't give %s %d arguments", inner, len(args))
+	}
+	if fn.panicv == nil {
+		return
+	}
+	pass, err = inner.Matches(append([]interface{}{fn.panicv}, args...))
+	if err == nil {
+		err = inner.Error()
+	}
+	return
+}
+
+//  Show the value and stack trace of a function call's panic.
+func explainPanic(args []interface{}) string {
+	fn, ok := args[0].(FnCall)
+	if !ok {
+		return ""
+	}
+	if fn.panicv == nil {
+		return "did not panic"
+	}
+	why := ""
+	if len(args) > 1 {
+		if e, ok := args[1].(explainer); ok {
+			why = e.explain(append([]interface{}{fn.panicv}, args[2:]...))
+		}
+	}
+	if why != "" {
+		why += "\n"
+	}
+	return why + explainPanicked(args)
+}
+
+//  Show the value and stack trace of a function call's panic, when a negated
+//  panic matcher fails.
+func explainPanicked(args []interface{}) string {
+	fn, ok := args[0].(FnCall)
+	if !ok || fn.panicv == nil {
+		return ""
+	}
+	return fmt.Sprintf("panicked with %#v\n%s", fn.panicv, fn.stack)
+}
@@ -0,0 +1,54 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    panic_test.go
+ *  Description: For testing panic.go
+ */
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestPanicMatchers(T *testing.T) {
+	describePassing(T, "Panic matchers", func(s *SpecTest) {
+		s.It("match", func() {
+			s.Spec(func() { panic("boom") }, Should, PanicWith, "boom")
+			s.Spec(func() { panic("boom") }, Should, Not, PanicWith, "bang")
+			s.Spec(func() {}, Should, Not, PanicWith, nil)
+			s.Spec(func() { panic(fmt.Errorf("wrapped: %w", io.EOF)) }, Should, PanicWith, io.EOF)
+			s.Spec(func() { panic("index 3") }, Should, PanicMatching, MatchRegexp, `\d+`)
+			s.Spec(func() { panic(3) }, Should, PanicMatching, BeBetween, 1, 5)
+			s.Spec(func() {}, Should, Not, PanicMatching, Satisfy, func(interface{}) bool { return true })
+		})
+	})
+}
+
+func TestPanicDetail(T *testing.T) {
+	root := runDescribed("Panic matchers", func(s *SpecTest) {
+		s.It("explain failures", func() {
+			s.Spec(func() { panic("boom") }, Should, PanicWith, "bang")
+			s.Spec(func() { panic("index x") }, Should, PanicMatching, MatchRegexp, `\d+`)
+			s.Spec(func() {}, Should, Panic)
+			s.Spec(func() { panic("boom") }, Should, Not, Panic)
+			s.Spec(func() { panic("boom") }, Should, Not, PanicMatching, MatchRegexp, "o+")
+		})
+		s.It("report errors", func() {
+			s.Spec(func() int { panic("boom") }, Should, Equal, 1)
+			s.Spec(func() {}, Should, PanicMatching, 1)
+		})
+	})
+	expectResults(T, root.Children[0], Failed,
+		"panicked with \"boom\"\ngoroutine...",
+		"\"index x\" does not match /\\d+/\npanicked with \"index x\"...",
+		"did not panic",
+		"panicked with \"boom\"\ngoroutine...",
+		"panicked with \"boom\"\ngoroutine...")
+	expectResults(T, root.Children[1], Errored,
+		"function call panicked: boom\ngoroutine...",
+		"PanicMatching needs a Matcher, not int")
+}
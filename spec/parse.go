@@ -8,7 +8,11 @@ package spec
  */
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
 )
 //  Syntactic sugar for Spec sequences. See Spec.
 type Sugar uint8
@@ -28,21 +32,87 @@ func (s Sugar) String() string {
 	panic(ErrBadSugar)
 }
 
-//  A call to a nil-adic function. Output values can be accessed.
+//  A call to a function. Output values can be accessed.
 type FnCall struct {
 	fn     reflect.Value
+	in     []reflect.Value // Arguments given by Call
+	err    error           // Arguments given by Call that fn can't take
+	called bool
 	panicv interface{}
+	stack  []byte // The stack trace of a panic
 	out    []reflect.Value
 }
 
+//  Create a function call Value that calls fn with args when the Spec is
+//  evaluated. Each argument must be assignable to its parameter; nil gives
+//  the zero value. Variadic functions can be given any number of arguments
+//  after their fixed parameters.
+//      s.Spec(Call(strconv.Atoi, "12"), Should, Not, HaveError)
+//      s.Spec(Call(fmt.Sprint, "a", 1), Should, Equal, "a1")
+func Call(fn interface{}, args ...interface{}) FnCall {
+	call := FnCall{fn: reflect.ValueOf(fn), in: make([]reflect.Value, len(args))}
+	for i := range args {
+		call.in[i] = reflect.ValueOf(args[i])
+	}
+	if call.fn.Kind() != reflect.Func {
+		call.err = fmt.Errorf("Call needs a function, not %T", fn)
+		return call
+	}
+	typ := call.fn.Type()
+	if n := typ.NumIn(); typ.IsVariadic() && len(args) < n-1 || !typ.IsVariadic() && len(args) != n {
+		call.err = fmt.Errorf("Call can't give %d arguments to a %s", len(args), typ)
+		return call
+	}
+	for i, arg := range args {
+		var ptyp reflect.Type
+		if typ.IsVariadic() && i >= typ.NumIn()-1 {
+			ptyp = typ.In(typ.NumIn() - 1).Elem()
+		} else {
+			ptyp = typ.In(i)
+		}
+		switch v := call.in[i]; {
+		case !v.IsValid() && canBeNil(ptyp.Kind()):
+			call.in[i] = reflect.Zero(ptyp)
+		case !v.IsValid() || !v.Type().AssignableTo(ptyp):
+			call.err = fmt.Errorf("Call can't give %#v to a %s argument", arg, ptyp)
+			return call
+		}
+	}
+	return call
+}
+
+//  Describe the function call, like Call(strconv.Atoi, "12").
+func (fn FnCall) String() string {
+	name := "nil"
+	switch {
+	case fn.fn.Kind() == reflect.Func:
+		name = fn.fn.Type().String()
+		if f := runtime.FuncForPC(fn.fn.Pointer()); f != nil {
+			name = f.Name()
+		}
+	case fn.fn.IsValid():
+		name = fmt.Sprintf("%#v", fn.fn.Interface())
+	}
+	s := []string{name}
+	for _, v := range fn.in {
+		if v.IsValid() {
+			s = append(s, fmt.Sprintf("%#v", v.Interface()))
+		} else {
+			s = append(s, "nil")
+		}
+	}
+	return fmt.Sprintf("Call(%s)", strings.Join(s, ", "))
+}
+
 func (fn FnCall) call() (gn FnCall) {
-	gn.fn = fn.fn
+	gn.fn, gn.in, gn.called = fn.fn, fn.in, true
 	defer func() {
 		if e := recover(); e != nil {
 			gn.panicv = e
+			gn.stack = debug.Stack()
 		}
 	}()
-	gn.out = gn.fn.Call([]reflect.Value{})
+	gn.out = gn.fn.Call(gn.in)
 	return
 }
 
@@ -136,6 +206,10 @@ func (t *SpecTest) parse(seq sequence) (m Matcher, negated bool, args []interfac
 
 	t.doDebug(func() { t.Log(specString(seq[i:])) })
 	// Look for Should separating value and function.
+	if i >= len(seq) {
+		err = ErrMissingSugar
+		return
+	}
 	switch seq[i].token {
 	case tSugar:
 		if seq[i].value.(Sugar) != Should {
@@ -145,9 +219,16 @@ func (t *SpecTest) parse(seq sequence) (m Matcher, negated bool, args []interfac
 		err = ErrUnexpectedValue
 	}
 	i++
+	if err != nil {
+		return
+	}
 
 	// Look for Not separating value and function.
 	negated = false
+	if i >= len(seq) {
+		err = errors.New("Missing Matcher")
+		return
+	}
 	switch seq[i].token {
 	case tSugar:
 		if seq[i].value.(Sugar) != Not {
@@ -156,6 +237,9 @@ func (t *SpecTest) parse(seq sequence) (m Matcher, negated bool, args []interfac
 		negated = true
 		i++
 	}
+	if err != nil {
+		return
+	}
 
 	if negated {
 		t.doDebug(func() { t.Log(specString(seq[i:])) })
@@ -167,25 +251,41 @@ func (t *SpecTest) parse(seq sequence) (m Matcher, negated bool, args []interfac
 		return
 	}
 
-	args = make([]interface{}, 1, 2)
-	args[0] = v1
-	if m.NumIn() > 1 {
-		// Look for a Matcher argument if necessary.
-		if m.NumIn() > 2 {
-			err = errors.New("Needs too many arguments")
+	// Apply the Matcher to each of several selected results.
+	if r, ok := v1.(results); ok {
+		if v, ok := m.(VariadicMatcher); ok && v.IsVariadic() {
+			err = fmt.Errorf("Variadic %s can't match %d results", m, len(r.pos))
 			return
 		}
+		m = &resultsMatch{m: m, n: len(r.pos)}
+	}
+
+	args = make([]interface{}, 1, m.NumIn())
+	args[0] = v1
+	// Look for as many Matcher arguments as necessary.
+	for len(args) < m.NumIn() {
 		t.doDebug(func() { t.Log(specString(seq[i:])) })
-		k, v2, err = t.parseValue(seq[i:])
+		k, v2, err = t.parseArg(seq[i:])
 		i += k
 		if err != nil {
 			return
 		}
-		if i < len(seq) {
-			err = errors.New("Excess specification pieces")
-		}
 		args = append(args, v2)
 	}
+	// Variadic Matchers take the remaining pieces as arguments.
+	if v, ok := m.(VariadicMatcher); ok && v.IsVariadic() {
+		for i < len(seq) {
+			k, v2, err = t.parseArg(seq[i:])
+			i += k
+			if err != nil {
+				return
+			}
+			args = append(args, v2)
+		}
+	}
+	if i < len(seq) {
+		err = errors.New("Excess specification pieces")
+	}
 	return
 }
 
@@ -221,63 +321,56 @@ func (t *SpecTest) parseValue(seq sequence) (i int, v interface{}, err error) {
 	}
 
 	// The object of the Spec Function.
-	v = valpieces[0].value
-	valpieces[0].kind = kNative
+	v, valpieces[0].kind, err = evalValue(valpieces[0].value)
+	if err != nil {
+		return
+	}
 
-	// Evaluate nil-adic function calls.
-	switch v.(type) {
-	case FnCall:
-	default:
-		fntyp := reflect.TypeOf(v)
-		if k := fntyp.Kind(); k != reflect.Func {
-			break
-		}
-		if fntyp.NumIn() != 0 {
-			break
-		}
-		if fntyp.NumOut() == 0 {
-			err = errors.New("Value-less function")
+	// Index the Value to create another Value.
+	for _, piece := range valpieces[1:] {
+		if v, err = indexSpecValue(v, piece.value); err != nil {
 			return
 		}
-		valpieces[0].kind = kFnCall
-		fnval := reflect.ValueOf(v)
-		v = FnCall{fn: fnval}.call()
 	}
+	v = selectedValue(v)
+	return
+}
 
-	// Return when no indexing values are given.
-	if len(valpieces) <= 1 {
+//  Parse a single piece used as a Matcher argument.
+func (t *SpecTest) parseArg(seq sequence) (i int, v interface{}, err error) {
+	if len(seq) == 0 {
+		err = errors.New("Missing argument")
 		return
 	}
-
-	if len(valpieces) > 2 {
-		err = errors.New("Too many Value pieces")
+	if seq[0].token == tSugar {
+		err = ErrUnexpectedSugar
 		return
 	}
+	v, seq[0].kind, err = evalValue(seq[0].value)
+	i = 1
+	return
+}
 
-	// Create a slice of reflect.Values to index
-	var vals []reflect.Value
-	switch v.(type) {
+//  Evaluate a Value that is a nil-adic function or a Call. Other Values are
+//  returned unchanged.
+func evalValue(v interface{}) (w interface{}, k kind, err error) {
+	w, k = v, kNative
+	switch fn := v.(type) {
 	case FnCall:
-		vals = v.(FnCall).out
-	default:
-		// Can't index the Value.
-		err = errors.New("Too many Value pieces")
+		// Calls created by Call are made when the Spec is evaluated.
+		if fn.err != nil {
+			err = fn.err
+		} else if !fn.called {
+			w = fn.call()
+		}
+		k = kFnCall
 		return
 	}
-
-	// Index the Value to create another Value.
-	index := valpieces[1]
-	switch index.value.(type) {
-	case int:
-		j := index.value.(int)
-		if j >= len(vals) {
-			err = errors.New("Index out of range")
-			return
-		}
-		v = vals[j].Interface()
-	default:
-		err = errors.New("Missing 'int' Value index")
+	fntyp := reflect.TypeOf(v)
+	if fntyp == nil || fntyp.Kind() != reflect.Func || fntyp.NumIn() != 0 {
+		return
 	}
+	w, k = FnCall{fn: reflect.ValueOf(v)}.call(), kFnCall
 	return
 }
 
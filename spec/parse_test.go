@@ -7,8 +7,111 @@ package spec
  *  Usage:       gotest
  */
 import (
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
     "testing"
 )
 
 func TestParse(T *testing.T) {
 }
+
+func TestParseArguments(T *testing.T) {
+	sum := MatcherMust(NewMatcher("Sum", func(x int, terms ...int) (bool, error) {
+		for _, t := range terms {
+			x -= t
+		}
+		return x == 0, nil
+	}))
+	three := func() int { return 3 }
+	s := NewSpecTestConfig(new(mockTest), Config{})
+	for _, test := range []struct {
+		seq   []interface{}
+		nargs int
+		fail  bool
+	}{
+		{[]interface{}{3, Should, BeBetween, 1, three}, 3, false},
+		{[]interface{}{6, Should, sum, 1, 2, three}, 4, false},
+		{[]interface{}{0, Should, sum}, 1, false},
+		{[]interface{}{3, Should, BeBetween, 1}, 0, true},
+		{[]interface{}{3, Should, Equal, 1, 2}, 0, true},
+		{[]interface{}{3, Should, Equal, Should}, 0, true},
+		{[]interface{}{3, Should, Not}, 0, true},
+		{[]interface{}{3}, 0, true},
+	} {
+		seq, _ := s.scan(test.seq)
+		_, _, args, err := s.parse(seq)
+		switch {
+		case test.fail && err == nil:
+			T.Errorf("parsed %s", specString(seq))
+		case !test.fail && err != nil:
+			T.Errorf("%s: %v", specString(seq), err)
+		case !test.fail && len(args) != test.nargs:
+			T.Errorf("%s: %d arguments", specString(seq), len(args))
+		}
+	}
+}
+
+func TestVariadicMatcher(T *testing.T) {
+	sum := MatcherMust(NewMatcher("Sum", func(x int, terms ...int) (bool, error) {
+		for _, t := range terms {
+			x -= t
+		}
+		return x == 0, nil
+	}))
+	describePassing(T, "A variadic matcher", func(s *SpecTest) {
+		s.It("takes the remaining arguments", func() {
+			s.Spec(6, Should, sum, 1, 2, func() int { return 3 })
+			s.Spec(func() int { return 6 }, Should, Not, sum, 1, 2)
+		})
+	})
+	if _, err := NewMatcher("Bad", func(x ...int) (bool, error) { return true, nil }); err == nil {
+		T.Error("created a matcher without a fixed argument")
+	}
+}
+
+func TestCall(T *testing.T) {
+	describePassing(T, "Call", func(s *SpecTest) {
+		s.It("calls functions with arguments", func() {
+			s.Spec(Call(strconv.Atoi, "12"), Should, Not, HaveError)
+			s.Spec(Call(strconv.Atoi, "12"), Should, Equal, 12)
+			s.Spec(Call(strconv.Atoi, "x"), Should, HaveError)
+			s.Spec(Call(strconv.Atoi, "12"), 0, 1, Should, Equal, 12, nil)
+			s.Spec(Call(fmt.Sprint, "a", 1), Should, Equal, "a1")
+			s.Spec(Call(fmt.Sprint), Should, Equal, "")
+			s.Spec(Call(errors.Is, nil, nil), Should, BeTrue)
+			s.Spec(1, Should, Equal, Call(strconv.Atoi, "1"))
+			s.Spec(Call(func(n int) int { panic(n) }, 3), Should, PanicWith, 3)
+		})
+	})
+}
+
+func TestCallErrors(T *testing.T) {
+	root := runDescribed("Call", func(s *SpecTest) {
+		s.It("reports bad arguments", func() {
+			s.Spec(Call(strconv.Atoi), Should, Equal, 1)
+			s.Spec(Call(strconv.Atoi, 1), Should, Equal, 1)
+			s.Spec(Call(strconv.Atoi, nil), Should, Equal, 1)
+			s.Spec(Call(1), Should, Equal, 1)
+			s.Spec(Call(nil), Should, Equal, 1)
+			s.Spec(Call(fmt.Sprintf), Should, Equal, 1)
+		})
+	})
+	expectResults(T, root.Children[0], Errored,
+		"Call can't give 0 arguments to a func(string) (int, error)",
+		"Call can't give 1 to a string argument",
+		"Call can't give <nil> to a string argument",
+		"Call needs a function, not int",
+		"Call needs a function, not <nil>",
+		"Call can't give 0 arguments to a func(string, ...interface {}) string")
+	for i, expect := range map[int]string{
+		1: "Call(strconv.Atoi, 1) Should Equal 1",
+		3: "Call(1) Should Equal 1",
+		4: "Call(nil) Should Equal 1",
+	} {
+		if r := root.Children[0].Results; len(r) > i && !strings.Contains(r[i].Spec, expect) {
+			T.Errorf("unexpected spec: %s", r[i].Spec)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    result.go
+ *  Description: Record and report the results of Spec calls.
+ */
+
+import (
+	"fmt"
+	"strings"
+)
+
+//  The outcome of a Spec call, or of a block of Spec calls.
+type Outcome uint8
+
+const (
+	Passed  Outcome = iota // The Spec held.
+	Failed                 // The Spec did not hold.
+	Errored                // The Spec could not be evaluated.
+	Pending                // The block is not implemented yet.
+	Skipped                // The block was skipped.
+)
+
+var outcomeStr = []string{
+	Passed:  "PASS",
+	Failed:  "FAIL",
+	Errored: "ERROR",
+	Pending: "PENDING",
+	Skipped: "SKIPPED",
+}
+
+func (o Outcome) String() string { return outcomeStr[o] }
+
+//  The result of a single Spec call.
+type SpecResult struct {
+	Index    int    // The position of the Spec call in its leaf, starting at 1.
+	Spec     string // A human-readable Spec sequence.
+	Outcome  Outcome
+	Err      error    // The reason an Errored Spec could not be evaluated.
+	Location Location // Where Spec was called.
+	Message  string   // A sentence describing a failure.
+	Detail   string   // An explanation of a failure given by the Matcher.
+}
+
+//  Returns a line describing the result.
+func (r SpecResult) String() string {
+	s := fmt.Sprintf("%s: %s #%d: %s", r.Location, r.Outcome, r.Index, r.Spec)
+	if r.Message != "" {
+		s += "\n\t\t" + r.Message
+	}
+	if r.Detail != "" {
+		s += "\n\t\t" + strings.Replace(r.Detail, "\n", "\n\t\t", -1)
+	}
+	if r.Err != nil {
+		s += fmt.Sprintf("\n\t\tError: %s", r.Err.Error())
+	}
+	return s
+}
+
+//  Count the results with each Outcome.
+func countOutcomes(results []SpecResult) (counts [Skipped + 1]int) {
+	for _, r := range results {
+		counts[r.Outcome]++
+	}
+	return
+}
+
+//  The Outcome of a block with the given results.
+func blockOutcome(counts [Skipped + 1]int) Outcome {
+	switch {
+	case counts[Errored] > 0:
+		return Errored
+	case counts[Failed] > 0:
+		return Failed
+	case counts[Skipped] > 0:
+		return Skipped
+	case counts[Pending] > 0:
+		return Pending
+	}
+	return Passed
+}
+
+//  Receives the results of each leaf executed by a SpecTest, after they are
+//  written to its Test. See Config.
+type Reporter interface {
+	Report(leaf *Node, results []SpecResult)
+}
+
+//  Write a message summarizing the Spec calls of the executed leaf.
+func (t *SpecTest) report() {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	if len(t.results) == 0 {
+		return
+	}
+	if t.config.Reporter != nil {
+		defer t.config.Reporter.Report(t.node, t.results)
+	}
+	counts := countOutcomes(t.results)
+	result := blockOutcome(counts)
+
+	// Write a message summarizing Spec calls.
+	msg := fmt.Sprintf("%s: %s: %s", t.node.Location, t.String(), result)
+	if result != Passed {
+		msg += fmt.Sprintf(" (%d passed, %d failed, %d errors",
+			counts[Passed], counts[Failed], counts[Errored])
+		if counts[Pending] > 0 {
+			msg += fmt.Sprintf(", %d pending", counts[Pending])
+		}
+		if counts[Skipped] > 0 {
+			msg += fmt.Sprintf(", %d skipped", counts[Skipped])
+		}
+		msg += ")"
+		for _, r := range t.results {
+			if r.Outcome != Passed {
+				msg += "\n\t" + r.String()
+			}
+		}
+	}
+
+	// Write the message as an error if there was a problem.
+	if result == Failed || result == Errored {
+		t.Error(msg)
+	} else {
+		t.Log(msg)
+	}
+}
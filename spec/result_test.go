@@ -0,0 +1,154 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    result_test.go
+ *  Description: For testing result.go
+ */
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestReportAllFailures(T *testing.T) {
+	mock := new(mockTest)
+	s := NewSpecTestConfig(mock, Config{})
+	var line int
+	root := s.Collect("results", func() {
+		s.It("are all recorded", func() {
+			line = callerLocation(0).Line + 1
+			s.Spec(1, Should, Equal, 2)
+			s.Spec(1, Should, Equal, 1)
+			s.Spec(3, Should, Equal, 4)
+			s.Spec(Should, Equal, 4)
+		})
+	})
+	s.Run(root)
+
+	results := root.Children[0].Results
+	var outcomes []string
+	for _, r := range results {
+		outcomes = append(outcomes, r.Outcome.String())
+	}
+	if s := strings.Join(outcomes, " "); s != "FAIL PASS FAIL ERROR" {
+		T.Errorf("unexpected outcomes %q", s)
+	}
+	if len(mock.errors) != 1 {
+		T.Fatalf("unexpected errors %q", mock.errors)
+	}
+	msg := mock.errors[0]
+	for _, expect := range []string{
+		"results are all recorded: ERROR (1 passed, 2 failed, 1 errors)",
+		fmt.Sprintf("result_test.go:%d: FAIL #1: 1 Should Equal 2", line),
+		fmt.Sprintf("result_test.go:%d: FAIL #3: 3 Should Equal 4", line+2),
+		"ERROR #4: Should Equal 4",
+	} {
+		if !strings.Contains(msg, expect) {
+			T.Errorf("%q not in message %q", expect, msg)
+		}
+	}
+	if strings.Contains(msg, "#2") {
+		T.Errorf("passing Spec in message %q", msg)
+	}
+}
+
+func TestResultLocation(T *testing.T) {
+	s := NewSpecTestConfig(new(mockTest), Config{})
+	var line int
+	root := s.Collect("locations", func() {
+		line = callerLocation(0).Line + 1
+		s.Spec(1, Should, Equal, 2)
+	})
+	s.Run(root)
+	if n := len(root.Children[0].Results); n != 1 {
+		T.Fatalf("%d results of a Spec in a Describe body", n)
+	}
+	r := root.Children[0].Results[0]
+	if r.Location.Line != line || !strings.HasSuffix(r.Location.File, "result_test.go") {
+		T.Errorf("unexpected location %s of a Spec in a Describe body", r.Location)
+	}
+}
+
+//  A mockTest whose Fatal methods end the goroutine, like those of testing.T.
+type exitingTest struct {
+	mockTest
+}
+
+func (m *exitingTest) Fatal(v ...interface{}) { m.Error(v...); runtime.Goexit() }
+func (m *exitingTest) Fatalf(format string, v ...interface{}) {
+	m.Fatal(fmt.Sprintf(format, v...))
+}
+func (m *exitingTest) FailNow() { m.Fail(); runtime.Goexit() }
+
+func TestReportAfterFatal(T *testing.T) {
+	mock := new(exitingTest)
+	s := NewSpecTestConfig(mock, Config{})
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		s.Describe("results", func() {
+			s.It("are reported after Fatal", func() {
+				s.Spec(1, Should, Equal, 2)
+				s.Fatal("stop")
+			})
+		})
+	}()
+	<-done
+	if len(mock.errors) != 2 || mock.errors[0] != "stop" || !strings.Contains(mock.errors[1], "FAIL #1: 1 Should Equal 2") {
+		T.Errorf("unexpected errors %q", mock.errors)
+	}
+}
+
+//  A mockTest that attributes errors to their callers like testing.T does,
+//  skipping the functions that called Helper.
+type helperTest struct {
+	mockTest
+	helpers map[string]bool
+	callers []string
+}
+
+func (m *helperTest) Helper() {
+	pc, _, _, _ := runtime.Caller(1)
+	m.helpers[runtime.FuncForPC(pc).Name()] = true
+}
+func (m *helperTest) Error(v ...interface{}) {
+	m.callers = append(m.callers, m.caller())
+	m.mockTest.Error(v...)
+}
+func (m *helperTest) Errorf(format string, v ...interface{}) {
+	m.callers = append(m.callers, m.caller())
+	m.mockTest.Errorf(format, v...)
+}
+
+//  The location of the first caller of Error that isn't a helper.
+func (m *helperTest) caller() string {
+	pc := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(3, pc)])
+	for {
+		f, more := frames.Next()
+		if !m.helpers[f.Function] || !more {
+			return fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line)
+		}
+	}
+}
+
+func TestReportHelper(T *testing.T) {
+	mock := &helperTest{helpers: make(map[string]bool)}
+	s := NewSpecTestConfig(mock, Config{})
+	line := callerLocation(0).Line + 1
+	s.Describe("results", func() {
+		s.It("are reported at the Describe call", func() {
+			s.Spec(1, Should, Equal, 2)
+		})
+	})
+	expect := fmt.Sprintf("result_test.go:%d", line)
+	if len(mock.callers) != 1 || mock.callers[0] != expect {
+		T.Errorf("errors reported at %q, not %s", mock.callers, expect)
+	}
+}
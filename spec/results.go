@@ -0,0 +1,241 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    results.go
+ *  Description: Index function call results and the values they return.
+ */
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//  Match every result of a function call with the expected values.
+//      s.Spec(func() (int, error) { return 45, nil }, Should, Results, 45, nil)
+var Results Matcher = &resultsMatcher{explained(MatcherMust(NewMatcher("Results", matcherResults)), explainResults).(*match)}
+
+//  The results of a function call selected by indexing it.
+type results struct {
+	fn  FnCall
+	pos []int
+}
+
+//  The values of the selected results.
+func (r results) values() []interface{} {
+	vals := make([]interface{}, len(r.pos))
+	for i, j := range r.pos {
+		vals[i] = r.fn.out[j].Interface()
+	}
+	return vals
+}
+
+func (r results) String() string {
+	vals := r.values()
+	s := make([]string, len(vals))
+	for i := range vals {
+		s[i] = formatSpecValue(vals[i])
+	}
+	return "(" + strings.Join(s, ", ") + ")"
+}
+
+//  The position of a function call result.
+func resultIndex(fn FnCall, key interface{}) (int, error) {
+	j, ok := key.(int)
+	if !ok {
+		return 0, errors.New("Missing 'int' Value index")
+	}
+	if j < 0 || j >= len(fn.out) {
+		return 0, errors.New("Index out of range")
+	}
+	return j, nil
+}
+
+//  An INDEX into the Value of a Spec, created by Elem or Key. Plain INDEXes
+//  only select the results of function calls.
+type Index struct {
+	key  interface{}
+	elem bool
+}
+
+//  Index a slice, array or string by position.
+//      s.Spec(fn, 0, Elem(1), Should, Equal, "b")
+func Elem(i int) Index { return Index{i, true} }
+
+//  Index a map by key.
+//      s.Spec(fn, 1, Key("key"), Should, Equal, 1)
+func Key(k interface{}) Index { return Index{k, false} }
+
+func (x Index) String() string {
+	if x.elem {
+		return fmt.Sprintf("Elem(%d)", x.key)
+	}
+	return fmt.Sprintf("Key(%#v)", x.key)
+}
+
+//  Index a Spec Value with key. Function calls are indexed by the position
+//  of their results, and further ints select more results. Other Values,
+//  including a single selected result, are indexed by an Index. A Matcher
+//  in the place of a key means the Spec is missing its Should.
+func indexSpecValue(v, key interface{}) (interface{}, error) {
+	switch key.(type) {
+	case Matcher, Sugar:
+		return nil, errors.New("Missing Should")
+	}
+	idx, isIndex := key.(Index)
+	switch x := v.(type) {
+	case FnCall:
+		if x.panicv != nil || len(x.out) == 0 {
+			return nil, fnerror{x}
+		}
+		if isIndex {
+			return nil, fmt.Errorf("Can't index function call %s with %s before selecting a result", x.fn.Type(), idx)
+		}
+		j, err := resultIndex(x, key)
+		return results{x, []int{j}}, err
+	case results:
+		if !isIndex {
+			j, err := resultIndex(x.fn, key)
+			return results{x.fn, append(x.pos[:len(x.pos):len(x.pos)], j)}, err
+		}
+		if len(x.pos) != 1 {
+			return nil, fmt.Errorf("Can't index results %s with %s", x, idx)
+		}
+		return indexValue(x.values()[0], idx)
+	}
+	if !isIndex {
+		return nil, fmt.Errorf("Can't index a %T with %v; use Elem or Key", v, key)
+	}
+	return indexValue(v, idx)
+}
+
+//  The Value of a single selected result. Other Values are unchanged.
+func selectedValue(v interface{}) interface{} {
+	if r, ok := v.(results); ok && len(r.pos) == 1 {
+		return r.values()[0]
+	}
+	return v
+}
+
+//  Index a slice, array or string with Elem, or a map with Key.
+func indexValue(v interface{}, x Index) (interface{}, error) {
+	rv := unwrapInterface(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.String:
+		if !x.elem {
+			return nil, fmt.Errorf("Can't index a %s with %s", rv.Type(), x)
+		}
+		j := x.key.(int)
+		if j < 0 || j >= rv.Len() {
+			return nil, errors.New("Index out of range")
+		}
+		return rv.Index(j).Interface(), nil
+	case reflect.Map:
+		if x.elem {
+			return nil, fmt.Errorf("Can't index a %s with %s", rv.Type(), x)
+		}
+		k := reflect.ValueOf(x.key)
+		switch {
+		case !k.IsValid() && canBeNil(rv.Type().Key().Kind()):
+			k = reflect.Zero(rv.Type().Key())
+		case !k.IsValid() || !k.Type().AssignableTo(rv.Type().Key()):
+			return nil, fmt.Errorf("Can't index a %s with %s", rv.Type(), x)
+		}
+		e := rv.MapIndex(k)
+		if !e.IsValid() {
+			return nil, fmt.Errorf("Missing key %#v", x.key)
+		}
+		return e.Interface(), nil
+	}
+	return nil, fmt.Errorf("Can't index a %T", v)
+}
+
+//  Applies a Matcher to each of several selected results. The arguments
+//  are split between the results, in order, so the Matcher can't be
+//  variadic.
+type resultsMatch struct {
+	m   Matcher
+	n   int // The number of results
+	err error
+	why string
+}
+
+func (r *resultsMatch) Matches(args []interface{}) (pass bool, err error) {
+	r.err, r.why = nil, ""
+	if len(args) != r.NumIn() {
+		return false, errors.New("wrong number of arguments")
+	}
+	res, ok := args[0].(results)
+	if !ok {
+		return false, fmt.Errorf("%s needs function call results, not %T", r.m, args[0])
+	}
+	k := r.m.NumIn() - 1
+	var whys []string
+	for i, val := range res.values() {
+		part := append([]interface{}{val}, args[1+i*k:1+(i+1)*k]...)
+		pass, why, err := runMatcher(r.m, part)
+		if err != nil {
+			r.err = fmt.Errorf("result %d: %v", res.pos[i], err)
+			return false, nil
+		}
+		if !pass {
+			whys = append(whys, fmt.Sprintf("result %d: %s", res.pos[i], nested(r.m, why)))
+		}
+	}
+	r.why = strings.Join(whys, "\n")
+	return len(whys) == 0, nil
+}
+
+func (r *resultsMatch) String() string                    { return r.m.String() }
+func (r *resultsMatch) Error() error                      { return r.err }
+func (r *resultsMatch) clone() Matcher                    { return &resultsMatch{m: cloneMatcher(r.m), n: r.n} }
+func (r *resultsMatch) NumIn() int                        { return 1 + r.n*(r.m.NumIn()-1) }
+func (r *resultsMatch) explain(args []interface{}) string { return r.why }
+
+func matcherResults(x interface{}, expected ...interface{}) (pass bool, err error) {
+	fn, ok := x.(FnCall)
+	if !ok {
+		return false, fmt.Errorf("Results needs a function call Value, not %T", x)
+	}
+	if fn.panicv != nil {
+		return false, fnerror{fn}
+	}
+	if len(expected) != len(fn.out) {
+		return false, fmt.Errorf("Results needs %d values, not %d", len(fn.out), len(expected))
+	}
+	for i := range fn.out {
+		if !reflect.DeepEqual(fn.out[i].Interface(), expected[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+//  A Results Matcher, describing failures as results instead of a value.
+type resultsMatcher struct {
+	*match
+}
+
+func (r *resultsMatcher) clone() Matcher { return &resultsMatcher{r.match.clone().(*match)} }
+
+func (r *resultsMatcher) FailureMessage(args []interface{}) string {
+	return fmt.Sprintf("expected results %s to be %s", formatSpecValue(args[0]), formatArgs(args[1:]))
+}
+func (r *resultsMatcher) NegatedFailureMessage(args []interface{}) string {
+	return fmt.Sprintf("expected results %s not to be %s", formatSpecValue(args[0]), formatArgs(args[1:]))
+}
+
+func explainResults(args []interface{}) string {
+	fn := args[0].(FnCall)
+	var whys []string
+	for i := range fn.out {
+		if diff := diffValues(fn.out[i].Interface(), args[1+i]); diff != "" {
+			whys = append(whys, fmt.Sprintf("result %d: %s", i, strings.Replace(diff, "\n", "\n\t", -1)))
+		}
+	}
+	return strings.Join(whys, "\n")
+}
@@ -0,0 +1,95 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    results_test.go
+ *  Description: For testing results.go
+ */
+
+import (
+	"io"
+	"testing"
+)
+
+func TestIndexResults(T *testing.T) {
+	pair := func() (int, error) { return 45, nil }
+	lists := func() ([]string, map[string]int, error) { return []string{"a", "b"}, map[string]int{"key": 1}, io.EOF }
+	str := func() (string, error) { return "ab", nil }
+	ints := func() ([]int, error) { return []int{1, 2}, io.EOF }
+	describePassing(T, "Indexed function calls", func(s *SpecTest) {
+		s.It("select results", func() {
+			s.Spec(pair, 0, 1, Should, Equal, 45, nil)
+			s.Spec(pair, 1, 0, Should, Not, Equal, 45, nil)
+			s.Spec(lists, 2, 0, Should, Not, BeNil)
+			s.Spec(str, 0, 1, Should, Equal, "ab", nil)
+			s.Spec(ints, 0, 1, Should, Equal, []int{1, 2}, io.EOF)
+			s.Spec(ints, 1, 0, Should, Equal, io.EOF, []int{1, 2})
+			s.Spec(pair, Should, Results, 45, nil)
+			s.Spec(pair, Should, Not, Results, 45, io.EOF)
+		})
+		s.It("index results", func() {
+			s.Spec(lists, 0, Elem(1), Should, Equal, "b")
+			s.Spec(lists, 1, Key("key"), Should, Equal, 1)
+			s.Spec(lists, 0, Elem(0), Elem(0), Should, Equal, byte('a'))
+			s.Spec(str, 0, Elem(1), Should, Equal, byte('b'))
+			s.Spec(ints, 0, Elem(1), Should, Equal, 2)
+			s.Spec([]int{1, 2}, Elem(1), Should, Equal, 2)
+			s.Spec(map[int]string{1: "x"}, Key(1), Should, Equal, "x")
+		})
+	})
+}
+
+func TestIndexResultsFailures(T *testing.T) {
+	pair := func() (int, error) { return 45, io.EOF }
+	root := runDescribed("Indexed function calls", func(s *SpecTest) {
+		s.It("explain failures", func() {
+			s.Spec(pair, 0, 1, Should, Equal, 45, nil)
+			s.Spec(pair, Should, Results, 44, io.EOF)
+		})
+		s.It("report errors", func() {
+			s.Spec(pair, 2, Should, Equal, 1)
+			s.Spec(pair, "a", Should, Equal, 1)
+			s.Spec(pair, 0, 1, "a", Should, Equal, 1, 1)
+			s.Spec(pair, 0, 1, Should, Equal, 45)
+			s.Spec(1, Elem(0), Should, Equal, 1)
+			s.Spec(map[string]int{}, Key("a"), Should, Equal, 1)
+			s.Spec([]int{1, 2}, 1, Should, Equal, 2)
+			s.Spec(pair, Elem(0), Should, Equal, 45)
+			s.Spec(pair, 0, 1, Elem(0), Should, Equal, 45, nil)
+			s.Spec([]int{1, 2}, Key(1), Should, Equal, 2)
+			s.Spec(map[int]string{1: "x"}, Elem(1), Should, Equal, "x")
+			s.Spec(pair, 0, 1, Should, HaveField, "Name", 1, 2)
+			s.Spec(pair, Should, Results, 45)
+			s.Spec(func() { panic("boom") }, 0, Should, Equal, 1)
+			s.Spec(1, Equal, 1)
+			s.Spec(pair, 0, Equal, 45)
+			s.Spec([]int{1, 2}, "a", Should, Equal, 2)
+		})
+	})
+	expectResults(T, root.Children[0], Failed,
+		"result 1: Equal failed\n\t&errors.errorString{s:\"EOF\"} != nil",
+		"result 0: 45 != 44")
+	if r := root.Children[0].Results; len(r) > 1 && r[1].Message != "expected results (45, EOF) to be 44, EOF" {
+		T.Errorf("unexpected message: %q", r[1].Message)
+	}
+	expectResults(T, root.Children[1], Errored,
+		"Index out of range",
+		"Missing 'int' Value index",
+		"Missing 'int' Value index",
+		"Missing argument",
+		"Can't index a int",
+		"Missing key \"a\"",
+		"Can't index a []int with 1; use Elem or Key",
+		"Can't index function call func() (int, error) with Elem(0) before selecting a result",
+		"Can't index results (45, EOF) with Elem(0)",
+		"Can't index a []int with Key(1)",
+		"Can't index a map[int]string with Elem(1)",
+		"Variadic HaveField can't match 2 results",
+		"Results needs 2 values, not 1",
+		"function call panicked: boom...",
+		"Missing Should",
+		"Missing Should",
+		"Can't index a []int with a; use Elem or Key")
+}
@@ -0,0 +1,331 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    run.go
+ *  Description: Run a collected tree of Nodes.
+ */
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//  Run the Specs of a tree collected with the Collect method. Leaves are
+//  executed in the order they were declared, unless the Config of t has a
+//  Seed. Only leaves whose description is selected by the Config of t are
+//  executed.
+//
+//  A trigger applies to the leaves declared after it in the same Describe
+//  block (including nested leaves). Triggers run only around leaves that
+//  are executed.
+//      Before All    runs before every leaf.
+//      Before First  runs before the first leaf.
+//      After All     runs after every leaf.
+//      After First   runs after the first leaf.
+//      After Last    runs after the last leaf.
+//
+//  A panic in a leaf, a trigger, or a Describe body is recovered and
+//  reported as an error, with its location and stack trace. A leaf is not
+//  executed when one of its Before triggers panics. Other leaves still run.
+//
+//  When the tree contains focused blocks (see FDescribe), only leaves in
+//  focused blocks are executed, and the test fails after the run. Leaves
+//  outside focused blocks are not executed by later runs of t either.
+//
+//  Pending leaves and leaves of a skipped Describe block are reported
+//  without executing them or their triggers. When running subtests, the
+//  subtests of pending and skipped leaves are marked skipped.
+func (t *SpecTest) Run(root *Node) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	if t.configErr != nil {
+		t.Fatalf("%s: %v", root.Location, t.configErr)
+		return
+	}
+	if t.fired == nil {
+		t.fired = make(map[*Node]bool)
+	}
+	root.Walk(func(n *Node) bool {
+		delete(t.fired, n)
+		return true
+	})
+	var focused []string
+	root.Walk(func(n *Node) bool {
+		if n.Focused {
+			focused = append(focused, fmt.Sprintf("%s: %s", n.Location, n))
+		}
+		return true
+	})
+	t.focus = t.focus || len(focused) > 0 || root.focused()
+	switch root.Kind {
+	case NodeContainer:
+		t.runContainer(root, nil)
+	case NodeLeaf:
+		t.runLeaf(root, nil)
+	}
+	if len(focused) > 0 {
+		t.Errorf("%s: %s: only focused blocks were run; remove the focus from\n\t%s",
+			root.Location, root, strings.Join(focused, "\n\t"))
+	}
+}
+
+//  Returns true if the leaf n should be executed.
+func (t *SpecTest) runnable(n *Node) bool {
+	if t.focus && !n.focused() {
+		return false
+	}
+	return (n.fn != nil || n.pending() || n.skipped() != nil) && t.selects(n)
+}
+
+//  Returns true if n is or contains a leaf that should be executed.
+func (t *SpecTest) hasRunnable(n *Node) (found bool) {
+	n.Walk(func(c *Node) bool {
+		found = found || c.Kind == NodeLeaf && t.runnable(c)
+		return !found
+	})
+	return
+}
+
+//  Run fn as a subtest named after n when subtests are enabled.
+func (t *SpecTest) subtest(n *Node, fn func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	if !t.config.Subtests || n.Text == "" {
+		fn()
+		return
+	}
+	r, ok := t.Test.(subtestRunner)
+	if !ok {
+		fn()
+		return
+	}
+	r.Run(n.Text, func(sub *testing.T) {
+		sub.Helper()
+		parent := t.Test
+		t.Test = sub
+		defer func() { t.Test = parent }()
+		fn()
+	})
+}
+
+func (t *SpecTest) runContainer(n *Node, hooks []*Node) {
+	if !t.hasRunnable(n) {
+		return
+	}
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	t.subtest(n, func() {
+		if h, ok := t.Test.(testHelper); ok {
+			h.Helper()
+		}
+		// Blocks keep the triggers declared before them when shuffled.
+		type step struct {
+			n     *Node
+			hooks []*Node
+		}
+		var steps []step
+		for _, c := range n.Children {
+			switch c.Kind {
+			case NodeBefore, NodeAfter:
+				// Copy on append so sibling blocks don't share triggers.
+				hooks = append(hooks[:len(hooks):len(hooks)], c)
+			case NodeContainer, NodeLeaf:
+				steps = append(steps, step{c, hooks})
+			}
+		}
+		if t.rand != nil {
+			t.rand.Shuffle(len(steps), func(i, j int) { steps[i], steps[j] = steps[j], steps[i] })
+		}
+		for _, s := range steps {
+			if s.n.Kind == NodeContainer {
+				t.runContainer(s.n, s.hooks)
+			} else {
+				t.runLeaf(s.n, s.hooks)
+			}
+		}
+		// After Last triggers are armed once a leaf in their scope has run.
+		for _, c := range n.Children {
+			if c.Kind == NodeAfter && c.Quantifier == Last && t.fired[c] {
+				if p := t.fire(c); p != nil {
+					r := panicResult(1, "After Last trigger", p)
+					t.Errorf("%s: %s: %s\n\t%s", c.Location, n, r.Outcome, r)
+				}
+			}
+		}
+	})
+}
+
+func (t *SpecTest) runLeaf(n *Node, hooks []*Node) {
+	if !t.runnable(n) {
+		return
+	}
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	outer := t.Test
+	t.subtest(n, func() {
+		if h, ok := t.Test.(testHelper); ok {
+			h.Helper()
+		}
+		parent := t.block
+		t.block = block{node: n}
+		defer func() { t.block = parent }()
+
+		t.leafBody(n, hooks)
+		if t.Test != outer {
+			if s, ok := t.Test.(skipper); ok {
+				if o := blockOutcome(countOutcomes(t.results)); o == Pending || o == Skipped {
+					s.SkipNow()
+				}
+			}
+		}
+	})
+}
+
+//  Record the results of the leaf n and report them. The results are
+//  reported even when the leaf ends the goroutine with Fatal or FailNow.
+func (t *SpecTest) leafBody(n *Node, hooks []*Node) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	defer t.reportLeaf(n)
+	switch skip := n.skipped(); {
+	case n.pending():
+		t.results = append(t.results, SpecResult{
+			Index:    1,
+			Spec:     "not implemented",
+			Outcome:  Pending,
+			Location: n.Location,
+		})
+	case skip != nil:
+		t.recordSkip(skip)
+	default:
+		t.execLeaf(n, hooks)
+	}
+}
+
+//  Store the results of the leaf n and report them.
+func (t *SpecTest) reportLeaf(n *Node) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	n.Results = t.results
+	t.report()
+}
+
+//  Execute the leaf n and its triggers.
+func (t *SpecTest) execLeaf(n *Node, hooks []*Node) {
+	// A panic is recorded as an error of the leaf. The leaf's body is
+	// skipped when a Before trigger panics or calls Skip, but After
+	// triggers still run.
+	ok := true
+	for _, h := range hooks {
+		if h.Kind == NodeBefore && (h.Quantifier == All || !t.fired[h]) {
+			t.fired[h] = true
+			p, skip := t.protect(h.Location, h.fn)
+			ok = t.recordPanic(h.Kind.String()+" "+h.Quantifier.String()+" trigger", p) && t.recordSkip(skip) && ok
+		}
+	}
+	if ok {
+		p, skip := t.protect(n.Location, n.fn)
+		t.recordPanic("block", p)
+		t.recordSkip(skip)
+	}
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.Kind != NodeAfter {
+			continue
+		}
+		switch {
+		case h.Quantifier == All:
+			t.recordPanic("After All trigger", t.fire(h))
+		case !t.fired[h]:
+			t.fired[h] = true
+			if h.Quantifier == First {
+				t.recordPanic("After First trigger", t.fire(h))
+			}
+		}
+	}
+}
+
+//  Execute the function of a trigger. Returns a panic of the trigger. Skip
+//  can't be called by After triggers, so a Skip call is reported as a panic.
+func (t *SpecTest) fire(h *Node) *errpanic {
+	t.doDebug(func() {
+		t.Logf("firing %s %s trigger from %s", h.Kind, h.Quantifier, h.Location)
+	})
+	p, skip := t.protect(h.Location, h.fn)
+	if skip != nil {
+		p = &errpanic{v: fmt.Sprintf("Skip(%q) called by an After trigger", skip.reason), loc: skip.loc}
+	}
+	return p
+}
+
+//  Raised by Skip to stop the block being executed or collected.
+type skipSignal struct {
+	reason string
+	loc    Location // Where Skip was called
+}
+
+//  Call fn, recovering a panic. A panic without a known location is given
+//  the location loc. A Skip call is returned separately from panics.
+func (t *SpecTest) protect(loc Location, fn func()) (p *errpanic, skip *skipSignal) {
+	defer func() {
+		if e := recover(); e != nil {
+			if s, ok := e.(skipSignal); ok {
+				skip = &s
+				return
+			}
+			ep := recovered(e)
+			if ep.loc.File == "" {
+				ep.loc = loc
+			}
+			p = &ep
+		}
+	}()
+	fn()
+	return nil, nil
+}
+
+//  The result of a panic in a block or trigger.
+func panicResult(index int, what string, p *errpanic) SpecResult {
+	return SpecResult{
+		Index:    index,
+		Spec:     fmt.Sprintf("%s panicked: %v", what, p.v),
+		Outcome:  Errored,
+		Err:      *p,
+		Location: p.loc,
+	}
+}
+
+//  Record a panic as an error of the running leaf. Returns true if there
+//  was no panic.
+func (t *SpecTest) recordPanic(what string, p *errpanic) bool {
+	if p == nil {
+		return true
+	}
+	t.results = append(t.results, panicResult(len(t.results)+1, what, p))
+	return false
+}
+
+//  Record a Skip call as a result of the running leaf. Returns true if
+//  there was no Skip call.
+func (t *SpecTest) recordSkip(skip *skipSignal) bool {
+	if skip == nil {
+		return true
+	}
+	t.results = append(t.results, SpecResult{
+		Index:    len(t.results) + 1,
+		Spec:     skip.reason,
+		Outcome:  Skipped,
+		Location: skip.loc,
+	})
+	return false
+}
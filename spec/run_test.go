@@ -0,0 +1,244 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    run_test.go
+ *  Description: For testing run.go
+ */
+
+import (
+	"strings"
+	"testing"
+)
+
+//  Returns a function making blocks that append a name to trace.
+func marker(trace *[]string) func(string) func() {
+	return func(s string) func() { return func() { *trace = append(*trace, s) } }
+}
+
+func TestRunTriggers(T *testing.T) {
+	s := NewSpecTestConfig(new(mockTest), Config{})
+	var trace []string
+	mark := marker(&trace)
+	s.Describe("triggers", func() {
+		s.It("a", mark("a"))
+		s.Before(All, mark("ba"))
+		s.Before(First, mark("bf"))
+		s.After(All, mark("aa"))
+		s.After(First, mark("af"))
+		s.After(Last, mark("al"))
+		s.It("b", mark("b"))
+		s.It("c", mark("c"))
+	})
+	expect := "a ba bf b af aa ba c aa al"
+	if s := strings.Join(trace, " "); s != expect {
+		T.Errorf("ran %q; expected %q", s, expect)
+	}
+}
+
+func TestRunPattern(T *testing.T) {
+	mock := new(mockTest)
+	s := NewSpecTestConfig(mock, Config{Pattern: "selected"})
+	var trace []string
+	s.Describe("pattern", func() {
+		s.Before(All, func() { trace = append(trace, "before") })
+		s.It("is selected", func() { s.Spec(1, Should, Equal, 1) })
+		s.It("is skipped", func() { trace = append(trace, "skipped") })
+	})
+	if s := strings.Join(trace, " "); s != "before" {
+		T.Errorf("ran %q", s)
+	}
+	if len(mock.logs) != 1 || !strings.HasSuffix(mock.logs[0], "is selected: PASS") {
+		T.Errorf("unexpected logs %q", mock.logs)
+	}
+}
+
+func TestRunPanics(T *testing.T) {
+	mock := new(mockTest)
+	s := NewSpecTestConfig(mock, Config{})
+	var trace []string
+	mark := marker(&trace)
+	root := s.Collect("panics", func() {
+		s.Describe("in a body", func() {
+			s.It("a", mark("a"))
+			panic("describe")
+		})
+		s.Describe("in a trigger", func() {
+			s.Before(First, func() { panic("before") })
+			s.After(All, mark("aa"))
+			s.It("b", mark("b"))
+			s.It("c", mark("c"))
+		})
+		s.It("d", func() {
+			s.Spec(1, Should, Equal, 1)
+			var m map[string]int
+			m["x"] = 1
+		})
+		s.It("e", mark("e"))
+		s.Spec(1, Should, Satisfy, func(int) bool { panic("matcher") })
+	})
+	s.Run(root)
+	if s := strings.Join(trace, " "); s != "a aa c aa e" {
+		T.Errorf("ran %q", s)
+	}
+	errored := make(map[string]SpecResult)
+	root.Walk(func(n *Node) bool {
+		for _, r := range n.Results {
+			if r.Outcome == Errored {
+				errored[n.String()] = r
+			}
+		}
+		return true
+	})
+	for path, expect := range map[string]string{
+		"panics in a body":      "Describe body panicked: describe",
+		"panics in a trigger b": "Before First trigger panicked: before",
+		"panics d":              "block panicked: assignment to entry in nil map",
+		"panics":                "1 Should Satisfy",
+	} {
+		r, ok := errored[path]
+		if !ok || !strings.HasPrefix(r.Spec, expect) || r.Location.String() == "???" {
+			T.Errorf("%s: unexpected result %s", path, r)
+		}
+	}
+	if r := errored["panics d"]; !strings.HasPrefix(r.Location.String(), "run_test.go:") || !strings.Contains(r.Err.Error(), "goroutine") {
+		T.Errorf("panic without location or stack: %s", r)
+	}
+	if len(mock.errors) != 4 {
+		T.Errorf("unexpected errors %q", mock.errors)
+	}
+}
+
+func TestRunPendingAndSkipped(T *testing.T) {
+	mock := new(mockTest)
+	s := NewSpecTestConfig(mock, Config{})
+	var trace []string
+	mark := marker(&trace)
+	root := s.Collect("blocks", func() {
+		s.Before(All, mark("ba"))
+		s.It("a")
+		s.PIt("b", mark("b"))
+		s.XDescribe("c", func() {
+			s.It("d", mark("d"))
+		})
+		s.PDescribe("e", func() {})
+		s.Describe("f", func() {
+			s.It("g", mark("g"))
+			s.Skip("no g")
+			s.It("never declared", mark("x"))
+		})
+		s.It("h", func() {
+			trace = append(trace, "h")
+			s.Skip("no h")
+			trace = append(trace, "unreachable")
+		})
+		s.It("i", mark("i"), func() { s.Spec(1, Should, Equal, 1) })
+	})
+	s.Run(root)
+	if s := strings.Join(trace, " "); s != "ba h ba i" {
+		T.Errorf("ran %q", s)
+	}
+	outcomes := make(map[string]string)
+	root.Walk(func(n *Node) bool {
+		if n.Kind == NodeLeaf {
+			r := n.Results[len(n.Results)-1]
+			outcomes[n.String()] = r.Outcome.String() + " " + r.Spec
+		}
+		return true
+	})
+	expected := map[string]string{
+		"blocks a":   "PENDING not implemented",
+		"blocks b":   "PENDING not implemented",
+		"blocks c d": "PENDING not implemented",
+		"blocks e":   "PENDING not implemented",
+		"blocks f":   "SKIPPED no g",
+		"blocks f g": "SKIPPED no g",
+		"blocks h":   "SKIPPED no h",
+		"blocks i":   "PASS 1 Should Equal 1",
+	}
+	if len(outcomes) != len(expected) {
+		T.Errorf("%d leaves; expected %d", len(outcomes), len(expected))
+	}
+	for path, expect := range expected {
+		if outcomes[path] != expect {
+			T.Errorf("%s: outcome %q; expected %q", path, outcomes[path], expect)
+		}
+	}
+	if len(mock.errors) != 0 {
+		T.Errorf("unexpected errors %q", mock.errors)
+	}
+	found := false
+	for _, msg := range mock.logs {
+		found = found || strings.Contains(msg, "blocks h: SKIPPED (0 passed, 0 failed, 0 errors, 1 skipped)")
+	}
+	if !found {
+		T.Errorf("skipped block not logged in %q", mock.logs)
+	}
+}
+
+func TestSkipOutsideBlock(T *testing.T) {
+	mock := new(mockTest)
+	NewSpecTestConfig(mock, Config{}).Skip("nothing")
+	if len(mock.errors) != 1 || !strings.Contains(mock.errors[0], "Skip called outside") {
+		T.Errorf("unexpected errors %q", mock.errors)
+	}
+}
+
+func TestRunFocused(T *testing.T) {
+	mock := new(mockTest)
+	s := NewSpecTestConfig(mock, Config{})
+	var trace []string
+	mark := marker(&trace)
+	s.Describe("focus", func() {
+		s.Before(All, mark("ba"))
+		s.It("a", mark("a"))
+		s.FIt("b", mark("b"))
+		s.FDescribe("c", func() {
+			s.It("d", mark("d"))
+			s.They("e", mark("e"))
+		})
+		s.Describe("f", func() {
+			s.FThey("g", mark("g"))
+			s.It("h", mark("h"))
+		})
+	})
+	if s := strings.Join(trace, " "); s != "ba b ba d ba e ba g" {
+		T.Errorf("ran %q", s)
+	}
+	if len(mock.errors) != 1 {
+		T.Fatalf("unexpected errors %q", mock.errors)
+	}
+	for _, expect := range []string{"only focused blocks were run", "focus b", "focus c", "focus f g"} {
+		if !strings.Contains(mock.errors[0], expect) {
+			T.Errorf("%q not in error %q", expect, mock.errors[0])
+		}
+	}
+
+	// The focus lasts for the blocks run afterwards.
+	mock = new(mockTest)
+	s = NewSpecTestConfig(mock, Config{})
+	trace = nil
+	s.Describe("focus", func() {
+		s.It("a", mark("a"))
+		s.FIt("b", mark("b"))
+	})
+	s.Describe("sibling", func() {
+		s.It("c", mark("c"))
+	})
+	if s := strings.Join(trace, " "); s != "b" || len(mock.errors) != 1 {
+		T.Errorf("ran %q with errors %q", s, mock.errors)
+	}
+
+	mock = new(mockTest)
+	s = NewSpecTestConfig(mock, Config{})
+	trace = nil
+	s.Describe("no focus", func() {
+		s.It("a", mark("a"))
+		s.It("b", mark("b"))
+	})
+	if s := strings.Join(trace, " "); s != "a b" || len(mock.errors) != 0 {
+		T.Errorf("ran %q with errors %q", s, mock.errors)
+	}
+}
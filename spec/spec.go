@@ -19,8 +19,8 @@ companion command Gospec.
 
 Specifications (or Specs) are defined by nesting them in a Describe call.
 Spec and Describe are methods of the SpecTest type, the primary type of "spec".
-The Describe method has aliases They and It. A new SpecTest is created with the
-NewSpecTest function.
+Spec calls are grouped into It (or They) blocks nested in Describe blocks. A
+new SpecTest is created with the NewSpecTest function.
 
 
     import (
@@ -59,17 +59,68 @@ The above example defines the following tests
     The "strconv" package integer conversion with Atoi converts decimal character strings to integers
     The "strconv" package integer conversion with Atoi can't convert hex character strings to integers
 
+Specs are executed in two phases. An outermost Describe call first collects a
+tree of the nested blocks and triggers (see Node), and then runs the leaves of
+that tree. The methods Collect and Run perform the phases separately so that
+the tree can be inspected before it is run.
+
+Tests created with NewSubtestSpecTest run every Describe, It, and They block
+as a subtest (see testing.T.Run), so the tooling of package "testing" can
+report and select individual specs by name.
+
+A Config given to NewSpecTestConfig selects the specs to run with regexp
+patterns and label expressions, shuffles them with a random seed, and adds a
+Reporter of results. NewSpecTest uses DefaultConfig, which reads the include
+pattern, exclude pattern, and label expression from the environment
+variables GOSPECPATTERN, GOSPECSKIP, and GOSPECLABELS.
+
+    s := NewSpecTestConfig(T, Config{SkipPattern: "slowly", Seed: 1})
+
+Blocks are labeled with the Labels method, and selected by label expressions
+like "integration && !slow".
+
+    s.Labels("integration", "slow").It("migrates the schema", func() { ... })
+
+Blocks that aren't implemented yet are declared with PIt, XIt, PDescribe or
+XDescribe, or with an It (or They) call without a function. A block calls
+Skip to stop and skip the rest of its specs. Pending and skipped blocks are
+reported as PENDING and SKIPPED without failing the test, and their subtests
+are marked skipped.
+
+    s.It("handles unicode")
+    s.It("talks to the database", func() {
+        if db == nil {
+            s.Skip("no database")
+        }
+        ...
+    })
+
+While debugging, FDescribe, FIt, and FThey focus on a few blocks. Only the
+leaves of focused blocks are run, here and in the blocks that the SpecTest
+runs afterwards, and the test fails with a list of the focused blocks so
+that they aren't committed.
+
+    s.FIt("handles unicode", func() { ... })
+    s.FDescribe("a connection", func() {
+        ...
+    })
+
 The spec package makes use of runtime reflection, predicate functions, and deep
 equality checking to be a flexible and lightweight test framework.
 
 The Spec argument sequence has the following grammar
 
-    VALUE [INDEX] Should [Not] FUNCTION [ARGUMENT]
+    VALUE [INDEX ...] Should [Not] FUNCTION [ARGUMENT ...]
 
 The general thinking is that (element INDEX of) VALUE is an object and FUNCTION
 acts as a method of VALUE with a boolean return type. The "Not" keyword
 obviously negates the returned value of FUNCTION.
 
+FUNCTION is followed by as many ARGUMENTs as its Matcher needs (see the NumIn
+method of Matcher). A VariadicMatcher takes every remaining piece of the
+sequence as an ARGUMENT. Like VALUE, an ARGUMENT that is a nil-adic function is
+called and its first return value is used.
+
 VALUE can be either a normal Go value (int, string, float64, struct, interface,
 ...). It can also be a nil-adic function with at least one return value. If
 VALUE is a nil-adic function, it is called before the spec is evaluated. If
@@ -77,6 +128,23 @@ INDEX is given, that nil-adic function return value is used as the object.
 The first nil-adic function return value is used an the object when no INDEX
 is given.
 
+A function that takes arguments is called by giving VALUE as Call(fn, args...).
+
+    s.Spec(Call(strconv.Atoi, "12"), Should, Not, HaveError)
+
+More INDEXes select more return values. The object is then a tuple of return
+values, and FUNCTION is applied to each of them with its own ARGUMENTs.
+
+    s.Spec(fn, 0, 1, Should, Equal, 45, nil)
+
+An int INDEX only selects return values. Elem(i) indexes a single return
+value that is a slice, array, or string, and Key(k) indexes one that is a
+map. Other VALUEs are indexed the same way. The Results matcher compares
+every return value.
+
+    s.Spec(fn, 1, Key("key"), Should, Equal, 1)
+    s.Spec(fn, Should, Results, 45, nil)
+
 FUNCTION can be any of the keywords
 
     Equal
@@ -85,33 +153,92 @@ FUNCTION can be any of the keywords
 
 Equal and Satisfy both require a single argument while HaveError requires none.
 Equal performs a deep equality test of the object against an argument object.
+When Equal fails, each difference is listed with the path (field, index, or
+map key) that leads to it.
 Satisfy requires a predicate function (boolean function of one argument) and
 is true if the predicate is true for the object. HaveError requires the object
 to be nil-adic which returns an error in its last return value. It returns true
 if the function returned an error.
+
+Variants of Equal are created with EqualWith. Its options can ignore struct
+fields, ignore the order of slice elements, allow floating point error, and
+more.
+
+    s.Spec(record, Should, EqualWith(IgnoreFields("ID"), IgnoreOrder()), expected)
+
+Slices, arrays, maps, strings, and channels can be inspected with the
+collection matchers Contain, ContainElements, ConsistOf, HaveLen, HaveCap,
+BeEmpty, HaveKey, and HaveKeyWithValue.
+
+    s.Spec(m, Should, HaveKeyWithValue, "a", 1)
+
+Numbers of any kind are compared with BeGreaterThan, BeLessThan, BeBetween,
+BeWithin, BeNaN, and BeInf.
+
+    s.Spec(math.Pi, Should, BeWithin, 3.14, 0.01)
+
+Text is inspected with MatchRegexp, HavePrefix, HaveSuffix, ContainSubstring,
+and EqualFold. They accept strings, byte slices, fmt.Stringers, and errors.
+A Spec whose matcher can't run, like MatchRegexp given a bad pattern, is
+reported as an error instead of a failure.
+
+    s.Spec(err, Should, MatchRegexp, `^open .*: no such file`)
+
+Errors, or the last return value of a function call, are inspected with
+HaveErrorMatching (errors.Is), HaveErrorOfType (errors.As), and
+HaveErrorMessage.
+
+    s.Spec(func() (int, error) { return r.Read(p) }, Should, HaveErrorMatching, io.EOF)
+
+A function call that panics is checked with Panic, PanicWith, or
+PanicMatching. Failures show the panic value and its stack trace. Other
+matchers report an error for a function call that panicked.
+
+    s.Spec(func() { panic("index 3") }, Should, PanicMatching, MatchRegexp, `\d+`)
+
+Eventually and Consistently wrap a Matcher to poll a function call, calling
+it again until its value matches or for as long as it keeps matching.
+Failures show the last value and the number of polls.
+
+    s.Spec(func() int { return len(done) }, Should, Eventually(Equal, 2*time.Second), 5)
+
+Channels are checked with Receive, BeClosed, and BeSent. Receive can wait
+for a value, store it, and match it.
+
+    s.Spec(results, Should, Receive, time.Second, Equal, 42)
+
+Matchers are combined with AllOf, AnyOf, And, Or, Negate, and WithTransform.
+The arguments after a combined Matcher are given to its Matchers in order.
+Negate is the Not sugar as a Matcher, for use inside other combinators.
+
+    s.Spec(x, Should, AllOf(BeGreaterThan, Negate(Equal)), 0, 5)
+
+A failed Spec is described with a sentence like "expected [1 2] to contain
+3", made from the name of its Matcher. Matchers can describe their own
+failures by implementing FailureMessager.
+
+Fields of structs, keys of maps, and the values of methods are matched with
+HaveField. MatchFields checks many fields at once, with Matchers given their
+arguments by Bind.
+
+    s.Spec(user, Should, HaveField, "Address.City", Equal, "Paris")
+
+Use BeNil rather than Equal to check for nil; it handles typed nil pointers,
+maps, slices, channels, functions, and interfaces. BeZero, BeTrue, BeFalse,
+BeAssignableTo, and Implement check other special values and types.
+
+    s.Spec(buf, Should, Implement, (*io.Writer)(nil))
 */
 package spec
 
 import (
 	"strings"
 	"regexp"
+	"testing"
 	"fmt"
-	"os"
+	"math/rand"
 )
 
-var SpecPattern = os.Getenv("GOSPECPATTERN")
-var specregexp *regexp.Regexp
-
-func (t SpecTest) getSpecRegexp() {
-	if len(SpecPattern) > 0 && specregexp == nil {
-		var err error
-		specregexp, err = regexp.Compile(SpecPattern)
-		if err != nil {
-			t.Fatalf("Can't compile GOSPECPATTERN %s", SpecPattern)
-		}
-	}
-}
-
 //  An abstraction of the type *testing.T with identical exported methods.
 type Test interface {
 	Log(...interface{})
@@ -125,6 +252,23 @@ type Test interface {
 	Failed() bool
 }
 
+//  Implemented by Tests that can run nested subtests, like *testing.T.
+type subtestRunner interface {
+	Run(name string, f func(*testing.T)) bool
+}
+
+//  Implemented by Tests that can attribute their output to the callers of
+//  helper functions, like *testing.T. Functions of package spec that report
+//  through the Test call Helper so that output refers to the spec file.
+type testHelper interface {
+	Helper()
+}
+
+//  Implemented by Tests that can be marked as skipped, like *testing.T.
+type skipper interface {
+	SkipNow()
+}
+
 type stringer interface {
     String() string
 }
@@ -145,6 +289,7 @@ func specString(spec sequence) string {
 
 type pos uint8
 
+//  Selects the nested Specs a trigger runs around. See Before and After.
 type Quantifier uint8
 
 const (
@@ -165,79 +310,41 @@ func errTrigger(pos string, q Quantifier) error {
 	return fmt.Errorf("Bad trigger %s %s", pos, q.String())
 }
 
-type trigger struct {
-	Quantifier
-	fn func()
-}
-
-func popTrigger(ptr *[]trigger) (back trigger) {
-	stack := *ptr
-	n := len(stack)
-	if n == 0 {
-		return
+//  Register a function to run before the Specs that are described after the
+//  call in the same Describe block. The Quantifier Last is not allowed.
+func (t *SpecTest) Before(q Quantifier, fn func()) error {
+	if q == Last {
+		return errTrigger("Before", q)
 	}
-	back = stack[n-1]
-	stack[n-1] = trigger{}
-	*ptr = stack[:n-1]
-	return
+	return t.addHook(NodeBefore, q, fn, callerLocation(1))
 }
 
-func popTriggerAt(i int, ptr *[]trigger) (tr trigger) {
-	n := len(*ptr)
-	if n == 0 {
-		return
-	} else if i >= n {
-		return
-	}
-	tstack := (*ptr)[:i+1]
-	tr = popTrigger(&(tstack))
-	copy((*ptr)[i:], (*ptr)[i+1:])
-	popTrigger(ptr)
-	return
-
+//  Register a function to run after the Specs that are described after the
+//  call in the same Describe block.
+func (t *SpecTest) After(q Quantifier, fn func()) error {
+	return t.addHook(NodeAfter, q, fn, callerLocation(1))
 }
 
-func (t SpecTest) Before(q Quantifier, fn func()) error {
-	t.doDebug(func() {
-		t.Logf("Before maketrigger")
-	})
-	return t.makeTrigger(&t.beforestack[t.depth-1], "Before", q, fn)
-}
-
-func (t SpecTest) After(q Quantifier, fn func()) error {
-	t.doDebug(func() {
-		t.Logf("After maketrigger")
-	})
-	return t.makeTrigger(&t.deferstack[t.depth-1], "After", q, fn)
-}
-
-func (t SpecTest) makeTrigger(stack *[]trigger, pos string, q Quantifier, fn func()) error {
-	if q == Last && pos == "Before" {
-		return errTrigger(pos, q)
-	}
-	s := *stack
-	s = append(s, trigger{q, fn})
-	t.doDebug(func() {
-		t.Logf("triggers %#v", s)
-	})
-	*stack = s
-	return nil
+//  The results of Spec calls made in the leaf being executed.
+type block struct {
+	node    *Node
+	results []SpecResult
 }
 
 //  The primary object of the spec package. Describe tests using the Describe,
 //  It, and They methods. Write individual tests using the Spec methods.
 type SpecTest struct {
 	Test
-	depth       int
-	spec        sequence
-	runspec     bool
-	passed      bool
-	ranspec     bool
-	err         error
-	beforestack [][]trigger
-	deferstack  [][]trigger
-	descstack   []string
-	debug       bool
+	block
+	collecting  *Node          // The Describe block being collected.
+	fired       map[*Node]bool // Triggers fired (or armed) in the current run.
+	focus       bool           // A tree run by t contained focused blocks.
+	config      Config
+	configErr   error          // A pattern of config that can't be compiled.
+	pattern     *regexp.Regexp // The compiled config.Pattern.
+	skipPattern *regexp.Regexp // The compiled config.SkipPattern.
+	labels      labelExpr      // The compiled config.Labels.
+	rand        *rand.Rand     // Orders blocks when config.Seed is nonzero.
 }
 
 //  Create a new SpecTest. Call this function at the begining of your test functions.
@@ -251,100 +358,195 @@ type SpecTest struct {
 //              ...
 //          })
 //      }
+//  The SpecTest uses DefaultConfig. See NewSpecTestConfig for other options.
 func NewSpecTest(T Test) *SpecTest {
-    return &SpecTest{Test: T, descstack: nil, debug: false}
+	return NewSpecTestConfig(T, DefaultConfig())
 }
 
-//  Execute a function if t.debug is true.
+//  Create a new SpecTest that runs each Describe, It, and They block as a
+//  subtest of T using T.Run. Every block then has its own pass/fail state and
+//  can be selected by name with the -test.run flag.
+//      func TestObject(T *testing.T) {
+//          s := NewSubtestSpecTest(T)
+//          s.Describe("My object", func() {
+//              ...
+//          })
+//      }
+//  Running "go test -run 'TestObject/My_object'" selects the block above.
+func NewSubtestSpecTest(T *testing.T) *SpecTest {
+	config := DefaultConfig()
+	config.Subtests = true
+	return NewSpecTestConfig(T, config)
+}
+
+//  Execute a function if debugging is configured.
 func (t *SpecTest) doDebug(fn func()) {
-	if t.debug {
+	if t.config.Debug {
 		fn()
 	}
 }
 
 //  Return a string describing the current tests being executed by t.
-func (t *SpecTest) String() string { return strings.Join(t.descstack, " ") }
+func (t *SpecTest) String() string {
+	if t.collecting != nil {
+		return t.collecting.String()
+	}
+	return t.node.String()
+}
 
 //  Begin a block that describes a given thing. Can be called again from the
 //  does function to describe more specific elements of that thing.
+//
+//  An outermost Describe first collects the tree of nested blocks by calling
+//  the does functions of nested Describe blocks. The bodies of nested It and
+//  They blocks are executed afterwards, when the collected tree is run (see
+//  Collect and Run).
 func (t *SpecTest) Describe(thing string, does func()) {
-	t.getSpecRegexp()
-
-	t.descstack = append(t.descstack, thing)
-	t.beforestack = append(t.beforestack, nil)
-	t.deferstack = append(t.deferstack, nil)
-	t.depth++
-
-	oldrunspec := t.runspec
-	if specregexp != nil && !specregexp.MatchString(t.String()) {
-		t.runspec = false
-	} else if specregexp != nil {
-		t.runspec = true
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
 	}
+	t.describe(&Node{Text: thing}, does, 1)
+}
 
-	defer func() {
-		// Clear the SpecTest when the description's scope is left.
-		t.depth--
-		t.descstack = t.descstack[:t.depth]
-		popTrigger(&t.beforestack[t.depth])
-		popTrigger(&t.deferstack[t.depth])
-		t.spec = nil
-		t.passed = true
-		t.ranspec = false
-		t.err = nil
-		t.runspec = oldrunspec
-	}()
-
-	after := t.deferstack
-	if k := len(after[t.depth-1]); k > 0 {
-		for j := 0; j < k; j++ {
-			tr := after[t.depth-1][j]
-			if tr.Quantifier == Last {
-				defer tr.fn()
-			}
-		}
+//  Begin a block containing calls to Spec. The check function is executed
+//  when the tree containing the block is run. A block without a check
+//  function is reported as PENDING.
+//      s.It("handles unicode")
+func (t *SpecTest) It(specification string, check ...func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
 	}
-	t.deferstack = after
-
-	// Do the described tests.
-	does()
-	if t.ranspec {
-		// Compute the result of executed Spec calls.
-        ok := t.passed && t.err == nil
-        var result string
-		switch {
-        case ok:
-		    result = "PASS"
-        case t.err != nil:
-			result = "ERROR"
-		case !t.passed:
-			result = "FAIL"
-        default:
-            panic("unexpected outcome")
-		}
+	t.it(&Node{Text: specification}, check, 1)
+}
 
-		// Write a message summarizing Spec calls.
-		msg := fmt.Sprintf("%s: %s", t.String(), result)
-		if !ok {
-			msg += fmt.Sprintf("\n\t%s", specString(t.spec))
-		}
-		if t.err != nil {
-			msg += fmt.Sprintf("\n\tError: %s", t.err.Error())
-		}
+//  A synonymn of It.
+func (t *SpecTest) They(specification string, check ...func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	t.it(&Node{Text: specification}, check, 1)
+}
 
-		// Write the message as an error if there was a problem.
-		if ok {
-			t.Log(msg)
-		} else {
-			t.Error(msg)
+//  Declare an It block that is not implemented yet. The check function is
+//  not executed, and the block is reported as PENDING.
+func (t *SpecTest) PIt(specification string, check ...func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	t.it(&Node{Text: specification}, nil, 1)
+}
+
+//  A synonymn of PIt.
+func (t *SpecTest) XIt(specification string, check ...func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	t.it(&Node{Text: specification}, nil, 1)
+}
+
+//  Declare a Describe block that is not implemented yet. The does function
+//  is still executed to collect the nested blocks, which are all reported
+//  as PENDING.
+func (t *SpecTest) PDescribe(thing string, does func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	t.describe(&Node{Text: thing, Pending: true}, does, 1)
+}
+
+//  A synonymn of PDescribe.
+func (t *SpecTest) XDescribe(thing string, does func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	t.describe(&Node{Text: thing, Pending: true}, does, 1)
+}
+
+//  Declare a focused Describe block. When a tree contains focused blocks,
+//  only the leaves of focused blocks are run and the test fails, so that
+//  focused blocks are not committed by accident. Trees the SpecTest runs
+//  afterwards also run only their focused leaves.
+func (t *SpecTest) FDescribe(thing string, does func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	t.describe(&Node{Text: thing, Focused: true}, does, 1)
+}
+
+//  Declare a focused It block. See FDescribe.
+func (t *SpecTest) FIt(specification string, check ...func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	t.it(&Node{Text: specification, Focused: true}, check, 1)
+}
+
+//  A synonymn of FIt.
+func (t *SpecTest) FThey(specification string, check ...func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	t.it(&Node{Text: specification, Focused: true}, check, 1)
+}
+
+//  Declare the Container n where a declaring method like Describe was
+//  called. The argument skip is the number of stack frames from describe up
+//  to that method.
+func (t *SpecTest) describe(n *Node, does func(), skip int) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	n.Kind, n.Location = NodeContainer, callerLocation(skip+1)
+	t.declare(n, does)
+}
+
+//  Declare the leaf n, which is pending when it has no check function. The
+//  argument skip is like that of describe.
+func (t *SpecTest) it(n *Node, check []func(), skip int) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	n.Kind, n.Location = NodeLeaf, callerLocation(skip+1)
+	n.Pending = n.Pending || len(check) == 0
+	t.declare(n, checkBody(check))
+}
+
+//  A function calling each check function, or nil if there are none.
+func checkBody(check []func()) func() {
+	switch len(check) {
+	case 0:
+		return nil
+	case 1:
+		return check[0]
+	}
+	return func() {
+		for _, fn := range check {
+			fn()
 		}
 	}
 }
 
-//  A synonymn of Describe. It's function is meant to contain calls to Spec.
-func (t *SpecTest) It(specification string, check func()) { t.Describe(specification, check) }
-//  A synonymn of Describe. It's function is meant to contain calls to Spec.
-func (t *SpecTest) They(specification string, check func()) { t.Describe(specification, check) }
+//  Stop the block being executed and report it as SKIPPED. In the body of a
+//  Describe block, every block declared before the Skip call is skipped, and
+//  the blocks after it are never declared. The Describe block is reported as
+//  SKIPPED itself.
+//      s.It("talks to the database", func() {
+//          if db == nil {
+//              s.Skip("no database")
+//          }
+//          ...
+//      })
+func (t *SpecTest) Skip(reason string) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	loc := callerLocation(1)
+	if t.collecting == nil && t.node == nil {
+		t.Errorf("%s: Skip error: Skip called outside of a described block", loc)
+		return
+	}
+	panic(skipSignal{reason, loc})
+}
 
 //  Specify a relation between two objects.
 //      Spec("abc", Should, Equal, "abc")
@@ -353,73 +555,64 @@ func (t *SpecTest) They(specification string, check func()) { t.Describe(specifi
 //      Spec( v, Should, HaveError)
 //      Spec( v, Should, Equal, "abc")
 func (t *SpecTest) Spec(spec ...interface{}) {
-	// Run triggers regardless of context's matching status
-	before := t.beforestack
-	for i := 0; i < t.depth; i++ {
-		if k := len(before[i]); k > 0 {
-			for j := 0; j < k; j++ {
-				tr := before[i][j]
-				t.doDebug(func() {
-					t.Logf("firing %#v", tr)
-				})
-				tr.fn()
-				if tr.Quantifier == First {
-					popTriggerAt(j, &before[i])
-					k--
-					j--
-				}
-			}
-		}
-	}
-	t.beforestack = before
-	after := t.deferstack
-	for i := 0; i < t.depth; i++ {
-		if k := len(after[i]); k > 0 {
-			for j := 0; j < k; j++ {
-				tr := after[i][j]
-				t.doDebug(func() {
-					t.Logf("defering %#v", tr)
-				})
-				defer tr.fn()
-				if tr.Quantifier == First {
-					popTriggerAt(j, &after[i])
-					k--
-					j--
-				}
-			}
-		}
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
 	}
-	t.deferstack = after
+	t.spec(callerLocation(1), spec)
+}
 
-	// Don't run the spec if we are not in a matching context.
-	if !t.runspec {
+//  Evaluate a Spec called at a given location.
+func (t *SpecTest) spec(loc Location, spec []interface{}) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	if t.collecting != nil {
+		t.deferSpec(spec, loc)
+		return
+	}
+	if t.node == nil {
+		t.Errorf("%s: Spec error: Spec called outside of a described block", loc)
 		return
 	}
-
-	var (
-		seq     sequence
-		m       Matcher
-		negated bool
-		args    []interface{}
-	)
 
 	t.doDebug(func() {
 		t.Logf("Executing")
 	})
 
-	t.ranspec = true
-	seq, t.err = t.scan(spec)
-	if t.err != nil {
-		t.spec = seq
-		return
-	}
-	m, negated, args, t.err = t.parse(seq)
-	if t.err != nil {
-		t.spec = seq
-		return
+	r := SpecResult{Index: len(t.results) + 1, Location: loc}
+	seq, err := t.scan(spec)
+	if err == nil {
+		var (
+			m       Matcher
+			negated bool
+			args    []interface{}
+			passed  bool
+		)
+		m, negated, args, err = t.parse(seq)
+		if err == nil {
+			// Match with clones that keep the state of this Spec.
+			m, args = cloneMatcher(m), cloneArgs(args)
+			passed, err = t.exec(m, negated, args)
+		}
+		if passed {
+			r.Outcome = Passed
+		} else {
+			r.Outcome = Failed
+		}
+		if !passed && err == nil {
+			r.Message = failureMessage(m, negated, args)
+		}
+		if e, ok := m.(explainer); ok && !passed && !negated && err == nil {
+			r.Detail = e.explain(args)
+		}
+		if e, ok := m.(negatedExplainer); ok && !passed && negated && err == nil {
+			r.Detail = e.explainNegated(args)
+		}
 	}
-	t.exec(m, negated, args)
-	if !t.passed || t.err != nil {
-		t.spec = seq
+	if err != nil {
+		r.Outcome = Errored
+		r.Err = err
 	}
+	r.Spec = specString(seq)
+	t.results = append(t.results, r)
 }
@@ -11,8 +11,119 @@ package spec
  *  Usage:       gotest
  */
 import (
-    "testing"
+	"fmt"
+	"strings"
+	"testing"
 )
 
+//  A Test that records its output instead of reporting it.
+type mockTest struct {
+	logs   []string
+	errors []string
+	failed bool
+}
+
+func (m *mockTest) Log(v ...interface{})                 { m.logs = append(m.logs, fmt.Sprint(v...)) }
+func (m *mockTest) Logf(format string, v ...interface{}) { m.Log(fmt.Sprintf(format, v...)) }
+func (m *mockTest) Error(v ...interface{}) {
+	m.errors = append(m.errors, fmt.Sprint(v...))
+	m.failed = true
+}
+func (m *mockTest) Errorf(format string, v ...interface{}) { m.Error(fmt.Sprintf(format, v...)) }
+func (m *mockTest) Fatal(v ...interface{})                 { m.Error(v...) }
+func (m *mockTest) Fatalf(format string, v ...interface{}) { m.Errorf(format, v...) }
+func (m *mockTest) Fail()                                  { m.failed = true }
+func (m *mockTest) FailNow()                               { m.failed = true }
+func (m *mockTest) Failed() bool                           { return m.failed }
+
+//  Describe a thing with a SpecTest of its own. The errors of any failing
+//  Spec are reported to T.
+func describePassing(T *testing.T, thing string, does func(s *SpecTest)) {
+	mock := new(mockTest)
+	s := NewSpecTestConfig(mock, Config{})
+	s.Describe(thing, func() { does(s) })
+	if mock.Failed() {
+		T.Error(mock.errors)
+	}
+}
+
+//  Collect and run the description of a thing with a SpecTest of its own.
+//  Returns the root of the description.
+func runDescribed(thing string, does func(s *SpecTest)) *Node {
+	s := NewSpecTestConfig(new(mockTest), Config{})
+	root := s.Collect(thing, func() { does(s) })
+	s.Run(root)
+	return root
+}
+
+//  Check that each result of leaf has the given outcome, and the Detail of
+//  a failure or the error message of an error that is expected in the same
+//  position. An expectation ending with "..." only has to be a prefix.
+func expectResults(T *testing.T, leaf *Node, outcome Outcome, expect ...string) {
+	T.Helper()
+	if len(leaf.Results) != len(expect) {
+		T.Errorf("%q has %d results; expected %d", leaf.Text, len(leaf.Results), len(expect))
+		return
+	}
+	for i, r := range leaf.Results {
+		why := r.Detail
+		if r.Err != nil {
+			why = r.Err.Error()
+		}
+		ok := why == expect[i]
+		if prefix := strings.TrimSuffix(expect[i], "..."); prefix != expect[i] {
+			ok = strings.HasPrefix(why, prefix)
+		}
+		if r.Outcome != outcome || !ok {
+			T.Errorf("unexpected result: %s", r)
+		}
+	}
+}
+
 func TestSpec(T *testing.T) {
 }
+
+func TestSubtests(T *testing.T) {
+	s := NewSpecTestConfig(T, Config{Subtests: true})
+	var names []string
+	s.Describe("A subtest", func() {
+		s.It("is run with T.Run", func() {
+			names = append(names, s.Test.(*testing.T).Name())
+			s.Spec(1, Should, Equal, 1)
+		})
+	})
+	if len(names) != 1 || names[0] != "TestSubtests/A_subtest/is_run_with_T.Run" {
+		T.Errorf("unexpected subtest names %q", names)
+	}
+	if s.Test != Test(T) {
+		T.Error("Test was not restored after the subtest")
+	}
+}
+
+func TestSubtestsSkipped(T *testing.T) {
+	s := NewSpecTestConfig(T, Config{Subtests: true})
+	var sub *testing.T
+	s.Describe("A subtest", func() {
+		s.It("is pending")
+		s.It("is skipped", func() {
+			sub = s.Test.(*testing.T)
+			s.Skip("skipped")
+		})
+	})
+	if sub == nil || !sub.Skipped() {
+		T.Error("subtest calling Skip was not skipped")
+	}
+}
+
+func TestSubtestsFallback(T *testing.T) {
+	mock := new(mockTest)
+	s := NewSpecTestConfig(mock, Config{Subtests: true})
+	s.Describe("A non-testing.T Test", func() {
+		s.It("runs blocks in place", func() {
+			s.Spec(1, Should, Equal, 2)
+		})
+	})
+	if !mock.Failed() || len(mock.errors) != 1 {
+		T.Errorf("unexpected errors %q", mock.errors)
+	}
+}
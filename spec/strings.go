@@ -0,0 +1,174 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    strings.go
+ *  Description: Matchers for strings, byte slices, Stringers and errors.
+ */
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//  Matchers for text. The object and argument can be a string, a []byte, a
+//  fmt.Stringer, or an error (whose Error method gives the text).
+//      s.Spec(err, Should, MatchRegexp, `^open .*: no such file`)
+//      s.Spec(buf.Bytes(), Should, HavePrefix, "HTTP/1.1")
+//      s.Spec(path, Should, HaveSuffix, ".go")
+//      s.Spec(out, Should, ContainSubstring, "PASS")
+//      s.Spec("Go", Should, EqualFold, "GO")
+//  A MatchRegexp pattern that doesn't compile is reported as an error.
+var (
+	MatchRegexp      = explained(MatcherMust(NewMatcher("MatchRegexp", matcherMatchRegexp)), explainMatchRegexp)
+	HavePrefix       = explained(MatcherMust(NewMatcher("HavePrefix", matcherHavePrefix)), explainPrefix)
+	HaveSuffix       = explained(MatcherMust(NewMatcher("HaveSuffix", matcherHaveSuffix)), explainSuffix)
+	ContainSubstring = explained(MatcherMust(NewMatcher("ContainSubstring", matcherContainSubstring)), explainSubstring)
+	EqualFold        = explained(MatcherMust(NewMatcher("EqualFold", matcherEqualFold)), explainFold)
+)
+
+//  The text of a string-like Spec value.
+func stringOf(name string, x interface{}) (string, error) {
+	switch v := valueOfSpecValue(x).(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case error:
+		return v.Error(), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	if v := reflect.ValueOf(valueOfSpecValue(x)); v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("%s needs a string, []byte, fmt.Stringer or error, not %T", name, valueOfSpecValue(x))
+}
+
+//  The text of a string matcher's object and argument.
+func stringsOf(name string, x, y interface{}) (s, t string, err error) {
+	if s, err = stringOf(name, x); err != nil {
+		return
+	}
+	t, err = stringOf(name, y)
+	return
+}
+
+//  Point at the byte offset i of s where s diverged from what was expected.
+//  An offset inside a multi-byte character points at the character.
+//      "abXdef"
+//         ^ offset 2
+func divergence(s string, i int) string {
+	i = runeStart(s, i)
+	col := utf8.RuneCountInString(strconv.Quote(s[:i])) - 1
+	return fmt.Sprintf("%q\n%s^ offset %d", s, strings.Repeat(" ", col), i)
+}
+
+//  The offset of the start of the character of s containing byte offset i.
+func runeStart(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
+
+//  The length of the common prefix of s and t, in bytes.
+func commonPrefix(s, t string) int {
+	i := 0
+	for i < len(s) && i < len(t) && s[i] == t[i] {
+		i++
+	}
+	return i
+}
+
+func matcherMatchRegexp(x, pattern interface{}) (pass bool, err error) {
+	s, p, err := stringsOf("MatchRegexp", x, pattern)
+	if err != nil {
+		return
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return false, fmt.Errorf("MatchRegexp can't compile %q: %v", p, err)
+	}
+	return re.MatchString(s), nil
+}
+
+func explainMatchRegexp(args []interface{}) string {
+	s, p, _ := stringsOf("", args[0], args[1])
+	return fmt.Sprintf("%q does not match /%s/", s, p)
+}
+
+func matcherHavePrefix(x, prefix interface{}) (pass bool, err error) {
+	s, p, err := stringsOf("HavePrefix", x, prefix)
+	return err == nil && strings.HasPrefix(s, p), err
+}
+
+func explainPrefix(args []interface{}) string {
+	s, p, _ := stringsOf("", args[0], args[1])
+	return fmt.Sprintf("%s\ndiffers from prefix %q", divergence(s, commonPrefix(s, p)), p)
+}
+
+func matcherHaveSuffix(x, suffix interface{}) (pass bool, err error) {
+	s, p, err := stringsOf("HaveSuffix", x, suffix)
+	return err == nil && strings.HasSuffix(s, p), err
+}
+
+func explainSuffix(args []interface{}) string {
+	s, p, _ := stringsOf("", args[0], args[1])
+	// Find the common suffix.
+	i, j := len(s), len(p)
+	for i > 0 && j > 0 && s[i-1] == p[j-1] {
+		i, j = i-1, j-1
+	}
+	if i > 0 {
+		i--
+	}
+	return fmt.Sprintf("%s\ndiffers from suffix %q", divergence(s, i), p)
+}
+
+func matcherContainSubstring(x, sub interface{}) (pass bool, err error) {
+	s, p, err := stringsOf("ContainSubstring", x, sub)
+	return err == nil && strings.Contains(s, p), err
+}
+
+func explainSubstring(args []interface{}) string {
+	s, p, _ := stringsOf("", args[0], args[1])
+	// Point at the longest partial match.
+	at, n := 0, 0
+	for i := range s {
+		if k := commonPrefix(s[i:], p); k > n {
+			at, n = i, k
+		}
+	}
+	if n == 0 {
+		return fmt.Sprintf("%q does not contain %q", s, p)
+	}
+	end := runeStart(s, at+n)
+	return fmt.Sprintf("%s\nlongest partial match of %q ends before offset %d", divergence(s, end), p, end)
+}
+
+func matcherEqualFold(x, y interface{}) (pass bool, err error) {
+	s, t, err := stringsOf("EqualFold", x, y)
+	return err == nil && strings.EqualFold(s, t), err
+}
+
+func explainFold(args []interface{}) string {
+	s, t, _ := stringsOf("", args[0], args[1])
+	// Find the first rune that differs under case folding.
+	i := 0
+	for i < len(s) && len(t) > 0 {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		q, m := utf8.DecodeRuneInString(t)
+		if !strings.EqualFold(string(r), string(q)) {
+			break
+		}
+		i, t = i+n, t[m:]
+	}
+	return fmt.Sprintf("%s\ndiffers from %q", divergence(s, i), args[1])
+}
@@ -0,0 +1,72 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    strings_test.go
+ *  Description: For testing strings.go
+ */
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type name string
+
+func TestStringMatchers(T *testing.T) {
+	describePassing(T, "String matchers", func(s *SpecTest) {
+		s.It("match", func() {
+			s.Spec("abc123", Should, MatchRegexp, `^[a-z]+\d+$`)
+			s.Spec(errors.New("open x: no such file"), Should, MatchRegexp, `^open .*: no such file`)
+			s.Spec([]byte("HTTP/1.1 200 OK"), Should, HavePrefix, "HTTP/1.1")
+			s.Spec(bytes.NewBufferString("main.go"), Should, HaveSuffix, []byte(".go"))
+			s.Spec(name("gopher"), Should, ContainSubstring, "ph")
+			s.Spec("abc", Should, Not, ContainSubstring, "abd")
+			s.Spec("Go", Should, EqualFold, "GO")
+			s.Spec(func() string { return "go" }, Should, EqualFold, "Go")
+		})
+	})
+}
+
+func TestStringErrors(T *testing.T) {
+	root := runDescribed("String matchers", func(s *SpecTest) {
+		s.It("report errors", func() {
+			s.Spec("abc", Should, MatchRegexp, `a(b`)
+			s.Spec("abc", Should, Not, MatchRegexp, `a(b`)
+			s.Spec(1, Should, HavePrefix, "a")
+		})
+		s.It("match after an error", func() {
+			s.Spec("abc", Should, MatchRegexp, `b`)
+		})
+	})
+	expectResults(T, root.Children[0], Errored,
+		"MatchRegexp can't compile \"a(b\": error parsing regexp: missing closing ): `a(b`",
+		"MatchRegexp can't compile \"a(b\": error parsing regexp: missing closing ): `a(b`",
+		"HavePrefix needs a string, []byte, fmt.Stringer or error, not int")
+	expectResults(T, root.Children[1], Passed, "")
+}
+
+func TestStringExplanations(T *testing.T) {
+	for _, test := range []struct {
+		m      Matcher
+		args   []interface{}
+		expect string
+	}{
+		{HavePrefix, []interface{}{"abXdef", "abc"}, "\"abXdef\"\n   ^ offset 2\ndiffers from prefix \"abc\""},
+		{HaveSuffix, []interface{}{"main.go", ".c"}, "\"main.go\"\n       ^ offset 6\ndiffers from suffix \".c\""},
+		{ContainSubstring, []interface{}{"a\tbcd", "bcx"}, "\"a\\tbcd\"\n      ^ offset 4\nlongest partial match of \"bcx\" ends before offset 4"},
+		{ContainSubstring, []interface{}{"abc", "x"}, "\"abc\" does not contain \"x\""},
+		{EqualFold, []interface{}{"GoPher", "gopHEX"}, "\"GoPher\"\n      ^ offset 5\ndiffers from \"gopHEX\""},
+		{HavePrefix, []interface{}{"héllo", "hèllo"}, "\"héllo\"\n  ^ offset 1\ndiffers from prefix \"hèllo\""},
+		{HaveSuffix, []interface{}{"añb", "ób"}, "\"añb\"\n  ^ offset 1\ndiffers from suffix \"ób\""},
+		{ContainSubstring, []interface{}{"xhéllo", "hèllo"}, "\"xhéllo\"\n   ^ offset 2\nlongest partial match of \"hèllo\" ends before offset 2"},
+		{MatchRegexp, []interface{}{"abc", "^b"}, "\"abc\" does not match /^b/"},
+	} {
+		if s := test.m.(explainer).explain(test.args); s != test.expect {
+			T.Errorf("%s: unexpected explanation %q", test.m, s)
+		}
+	}
+}
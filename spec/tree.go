@@ -0,0 +1,260 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    tree.go
+ *  Description: Collect described blocks and triggers into a tree of Nodes.
+ */
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+//  A position in a Go source file.
+type Location struct {
+	File string
+	Line int
+}
+
+//  Returns the location as "file.go:line".
+func (loc Location) String() string {
+	if loc.File == "" {
+		return "???"
+	}
+	return fmt.Sprintf("%s:%d", filepath.Base(loc.File), loc.Line)
+}
+
+//  The location of a function call. The argument skip is the number of stack
+//  frames to ascend, with 0 identifying the caller of callerLocation.
+func callerLocation(skip int) Location {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return Location{}
+	}
+	return Location{file, line}
+}
+
+//  The location of the code that caused a panic, when called by a deferred
+//  function during the panic.
+func panicLocation() Location {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+	panicking := false
+	for {
+		f, more := frames.Next()
+		if panicking && !strings.HasPrefix(f.Function, "runtime.") {
+			return Location{f.File, f.Line}
+		}
+		if f.Function == "runtime.gopanic" {
+			panicking = true
+		}
+		if !more {
+			return Location{}
+		}
+	}
+}
+
+//  The type of a Node in a spec tree.
+type NodeKind uint8
+
+const (
+	NodeContainer NodeKind = iota // A Describe block.
+	NodeLeaf                      // An It or They block containing Spec calls.
+	NodeBefore                    // A trigger registered with Before.
+	NodeAfter                     // A trigger registered with After.
+)
+
+var nodeKindStr = []string{
+	NodeContainer: "Container",
+	NodeLeaf:      "Leaf",
+	NodeBefore:    "Before",
+	NodeAfter:     "After",
+}
+
+func (k NodeKind) String() string { return nodeKindStr[k] }
+
+//  An element of the tree collected from Describe, It, They, Before and After
+//  calls. Container bodies are executed while the tree is collected. Leaf
+//  bodies and triggers are only executed when the tree is run.
+//
+//  Spec calls made directly in a Describe body are collected into a Leaf
+//  with an empty Text. Such a Leaf is reported using the description of its
+//  Container.
+//
+//  The leaves of a pending Container are all pending. A pending Container
+//  without leaves is given one with an empty Text, and so is a skipped
+//  Container without a leaf of its own.
+type Node struct {
+	Kind       NodeKind
+	Text       string     // The description of a Container or Leaf.
+	Quantifier Quantifier // The Quantifier of a trigger.
+	Location   Location   // Where the Node was declared.
+	Pending    bool       // The block is not implemented yet.
+	Focused    bool       // The block was declared with FDescribe, FIt or FThey.
+	Labels     []string   // The labels of the block (see SpecTest.Labels).
+	Parent     *Node
+	Children   []*Node
+	Results    []SpecResult // The results of a Leaf's Spec calls once it has run.
+	fn         func()       // The body of a Leaf or trigger.
+	skip       *skipSignal  // The Skip call made in a Container's body.
+}
+
+//  Returns the full description of n, including the descriptions of its
+//  ancestors.
+func (n *Node) String() string {
+	var desc []string
+	for ; n != nil; n = n.Parent {
+		if n.Text != "" {
+			desc = append(desc, n.Text)
+		}
+	}
+	for i, j := 0, len(desc)-1; i < j; i, j = i+1, j-1 {
+		desc[i], desc[j] = desc[j], desc[i]
+	}
+	return strings.Join(desc, " ")
+}
+
+//  Returns true if n or one of its ancestors is pending.
+func (n *Node) pending() bool {
+	for ; n != nil; n = n.Parent {
+		if n.Pending {
+			return true
+		}
+	}
+	return false
+}
+
+//  Returns true if n or one of its ancestors is focused.
+func (n *Node) focused() bool {
+	for ; n != nil; n = n.Parent {
+		if n.Focused {
+			return true
+		}
+	}
+	return false
+}
+
+//  The Skip call made in the body of the nearest skipped ancestor of n, or
+//  nil.
+func (n *Node) skipped() *skipSignal {
+	for ; n != nil; n = n.Parent {
+		if n.skip != nil {
+			return n.skip
+		}
+	}
+	return nil
+}
+
+//  Call fn on n and each of its descendants in depth-first order. The
+//  descendants of a node are skipped when fn returns false.
+func (n *Node) Walk(fn func(*Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range n.Children {
+		c.Walk(fn)
+	}
+}
+
+//  Build the tree of a block without running any of its Specs. The returned
+//  Node can be inspected before it is given to the Run method.
+//      root := s.Collect("My object", func() {
+//          s.It("does something", func() { ... })
+//      })
+//      root.Walk(func(n *Node) bool {
+//          fmt.Println(n.Location, n.Kind, n)
+//          return true
+//      })
+//      s.Run(root)
+func (t *SpecTest) Collect(thing string, does func()) *Node {
+	n := &Node{Kind: NodeContainer, Text: thing, Location: callerLocation(1)}
+	t.collect(n, does)
+	return n
+}
+
+//  Add a block to the tree being collected. When no tree is being collected
+//  the block is collected and run immediately.
+func (t *SpecTest) declare(n *Node, body func()) {
+	if h, ok := t.Test.(testHelper); ok {
+		h.Helper()
+	}
+	if t.collecting == nil {
+		n.Parent = t.node
+		t.collect(n, body)
+		t.Run(n)
+		return
+	}
+	n.Parent = t.collecting
+	t.collecting.Children = append(t.collecting.Children, n)
+	t.collect(n, body)
+}
+
+//  Collect the body of n. Container bodies are executed immediately.
+func (t *SpecTest) collect(n *Node, body func()) {
+	if n.Kind == NodeLeaf {
+		n.fn = body
+		return
+	}
+	parent := t.collecting
+	t.collecting = n
+	defer func() { t.collecting = parent }()
+	// A panic in the body is reported by a leaf when the tree is run.
+	p, skip := t.protect(n.Location, body)
+	if p != nil {
+		leaf := &Node{Kind: NodeLeaf, Location: p.loc, Parent: n}
+		leaf.fn = func() { t.recordPanic("Describe body", p) }
+		n.Children = append(n.Children, leaf)
+	}
+	n.skip = skip
+	if n.Pending || n.skip != nil {
+		// The blocks after a Skip call are never declared, so a skipped
+		// Container is reported by a leaf of its own.
+		hasLeaf := false
+		n.Walk(func(c *Node) bool {
+			own := c.Parent == n && c.Text == ""
+			hasLeaf = hasLeaf || c.Kind == NodeLeaf && (n.skip == nil || own)
+			return !hasLeaf
+		})
+		if !hasLeaf {
+			n.Children = append(n.Children, &Node{Kind: NodeLeaf, Location: n.Location, Parent: n})
+		}
+	}
+}
+
+//  Add a trigger to the block being collected.
+func (t *SpecTest) addHook(kind NodeKind, q Quantifier, fn func(), loc Location) error {
+	if t.collecting == nil {
+		return fmt.Errorf("%s %s outside of a Describe block", kind, q)
+	}
+	n := &Node{Kind: kind, Quantifier: q, Location: loc, Parent: t.collecting, fn: fn}
+	t.collecting.Children = append(t.collecting.Children, n)
+	t.doDebug(func() {
+		t.Logf("%s trigger %s at %s", kind, q, loc)
+	})
+	return nil
+}
+
+//  Defer a Spec call made directly in the body of the Describe block being
+//  collected until the tree is run.
+func (t *SpecTest) deferSpec(spec []interface{}, loc Location) {
+	c := t.collecting
+	var leaf *Node
+	if k := len(c.Children); k > 0 && c.Children[k-1].Kind == NodeLeaf && c.Children[k-1].Text == "" {
+		leaf = c.Children[k-1]
+	} else {
+		leaf = &Node{Kind: NodeLeaf, Location: loc, Parent: c}
+		c.Children = append(c.Children, leaf)
+	}
+	body := leaf.fn
+	leaf.fn = func() {
+		if body != nil {
+			body()
+		}
+		t.spec(loc, spec)
+	}
+}
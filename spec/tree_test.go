@@ -0,0 +1,67 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    tree_test.go
+ *  Description: For testing tree.go
+ */
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollect(T *testing.T) {
+	s := NewSpecTestConfig(new(mockTest), Config{})
+	ran := false
+	root := s.Collect("An object", func() {
+		s.Before(All, func() {})
+		s.Describe("method", func() {
+			s.It("does something", func() { ran = true })
+		})
+		s.Spec(1, Should, Equal, 1)
+		s.Spec(2, Should, Equal, 2)
+		s.After(Last, func() {})
+	})
+	if ran {
+		T.Error("leaf body executed while collecting")
+	}
+
+	var kinds []string
+	var descs []string
+	root.Walk(func(n *Node) bool {
+		kinds = append(kinds, n.Kind.String())
+		descs = append(descs, n.String())
+		if !strings.HasPrefix(n.Location.String(), "tree_test.go:") {
+			T.Errorf("unexpected location %s of %s", n.Location, n.Kind)
+		}
+		return true
+	})
+	expect := "Container Before Container Leaf Leaf After"
+	if s := strings.Join(kinds, " "); s != expect {
+		T.Errorf("collected %q; expected %q", s, expect)
+	}
+	if descs[3] != "An object method does something" {
+		T.Errorf("unexpected description %q", descs[3])
+	}
+	if descs[4] != "An object" {
+		T.Errorf("unexpected description %q of Spec calls in Describe", descs[4])
+	}
+}
+
+func TestTriggerOutsideDescribe(T *testing.T) {
+	s := NewSpecTestConfig(new(mockTest), Config{})
+	if err := s.Before(All, func() {}); err == nil {
+		T.Error("Before outside of a Describe block succeeded")
+	}
+	root := s.Collect("x", func() {
+		if err := s.Before(Last, func() {}); err == nil {
+			T.Error("Before Last succeeded")
+		}
+	})
+	if len(root.Children) != 0 {
+		T.Error("Before Last was collected")
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    types.go
+ *  Description: Matchers for nil, zero and boolean values, and for types.
+ */
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//  Matchers for special values and for types.
+//      s.Spec(ptr, Should, BeNil)
+//      s.Spec(User{}, Should, BeZero)
+//      s.Spec(ok, Should, BeTrue)
+//      s.Spec(x, Should, BeAssignableTo, int64(0))
+//      s.Spec(x, Should, BeAssignableTo, reflect.TypeOf(x))
+//      s.Spec(buf, Should, Implement, (*io.Writer)(nil))
+//  BeNil passes for nil and for nil pointers, maps, slices, channels,
+//  functions, and interfaces, even inside an interface. BeAssignableTo
+//  takes a reflect.Type or a value of the type. Implement takes a nil
+//  pointer to an interface type.
+var (
+	BeNil          = explained(MatcherMust(NewMatcher("BeNil", matcherBeNil)), explainNil)
+	BeZero         = explained(MatcherMust(NewMatcher("BeZero", matcherBeZero)), explainZero)
+	BeTrue         = MatcherMust(NewMatcher("BeTrue", matcherBeTrue))
+	BeFalse        = MatcherMust(NewMatcher("BeFalse", matcherBeFalse))
+	BeAssignableTo = explained(MatcherMust(NewMatcher("BeAssignableTo", matcherBeAssignableTo)), explainAssignable)
+	Implement      = explained(MatcherMust(NewMatcher("Implement", matcherImplement)), explainImplement)
+)
+
+func matcherBeNil(x interface{}) (pass bool, err error) {
+	v := reflect.ValueOf(valueOfSpecValue(x))
+	return !v.IsValid() || canBeNil(v.Kind()) && v.IsNil(), nil
+}
+
+func explainNil(args []interface{}) string {
+	return fmt.Sprintf("%s is not nil", formatValue(reflect.ValueOf(valueOfSpecValue(args[0]))))
+}
+
+func matcherBeZero(x interface{}) (pass bool, err error) {
+	v := reflect.ValueOf(valueOfSpecValue(x))
+	return !v.IsValid() || v.IsZero(), nil
+}
+
+func explainZero(args []interface{}) string {
+	v := reflect.ValueOf(valueOfSpecValue(args[0]))
+	return fmt.Sprintf("%s is not the zero %s", formatValue(v), v.Type())
+}
+
+//  The value of a boolean Spec value.
+func boolOf(name string, x interface{}) (bool, error) {
+	v := reflect.ValueOf(valueOfSpecValue(x))
+	if v.Kind() != reflect.Bool {
+		return false, fmt.Errorf("%s needs a bool, not %T", name, valueOfSpecValue(x))
+	}
+	return v.Bool(), nil
+}
+
+func matcherBeTrue(x interface{}) (pass bool, err error) {
+	return boolOf("BeTrue", x)
+}
+
+func matcherBeFalse(x interface{}) (pass bool, err error) {
+	b, err := boolOf("BeFalse", x)
+	return err == nil && !b, err
+}
+
+//  The type given as a reflect.Type or as a value of the type.
+func typeOf(name string, t interface{}) (reflect.Type, error) {
+	switch t := t.(type) {
+	case reflect.Type:
+		return t, nil
+	case nil:
+		return nil, fmt.Errorf("%s needs a type, not nil", name)
+	}
+	return reflect.TypeOf(t), nil
+}
+
+func matcherBeAssignableTo(x, t interface{}) (pass bool, err error) {
+	typ, err := typeOf("BeAssignableTo", t)
+	if err != nil {
+		return
+	}
+	v := reflect.ValueOf(valueOfSpecValue(x))
+	if !v.IsValid() {
+		return canBeNil(typ.Kind()), nil
+	}
+	return v.Type().AssignableTo(typ), nil
+}
+
+func explainAssignable(args []interface{}) string {
+	typ, _ := typeOf("", args[1])
+	return fmt.Sprintf("%T is not assignable to %s", valueOfSpecValue(args[0]), typ)
+}
+
+//  The interface type pointed to by i, like (*io.Writer)(nil).
+func interfaceOf(name string, i interface{}) (reflect.Type, error) {
+	typ, ok := i.(reflect.Type)
+	if !ok {
+		typ = reflect.TypeOf(i)
+	}
+	if typ != nil && typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Interface {
+		return typ.Elem(), nil
+	}
+	return nil, fmt.Errorf("%s needs a pointer to an interface, like (*io.Writer)(nil), not %T", name, i)
+}
+
+func matcherImplement(x, i interface{}) (pass bool, err error) {
+	typ, err := interfaceOf("Implement", i)
+	if err != nil {
+		return
+	}
+	xtyp := reflect.TypeOf(valueOfSpecValue(x))
+	return xtyp != nil && xtyp.Implements(typ), nil
+}
+
+func explainImplement(args []interface{}) string {
+	typ, _ := interfaceOf("", args[1])
+	xtyp := reflect.TypeOf(valueOfSpecValue(args[0]))
+	if xtyp == nil {
+		return fmt.Sprintf("nil does not implement %s", typ)
+	}
+	var missing []string
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		if mx, ok := xtyp.MethodByName(m.Name); !ok {
+			if _, ok := reflect.PtrTo(xtyp).MethodByName(m.Name); ok {
+				missing = append(missing, m.Name+" (pointer receiver)")
+			} else {
+				missing = append(missing, m.Name)
+			}
+		} else if mx.Type.NumIn() > 0 && mx.Type.In(0) == xtyp {
+			// Compare signatures without the receiver.
+			in := make([]reflect.Type, mx.Type.NumIn()-1)
+			for j := range in {
+				in[j] = mx.Type.In(j + 1)
+			}
+			out := make([]reflect.Type, mx.Type.NumOut())
+			for j := range out {
+				out[j] = mx.Type.Out(j)
+			}
+			if reflect.FuncOf(in, out, mx.Type.IsVariadic()) != m.Type {
+				missing = append(missing, m.Name+" (wrong signature)")
+			}
+		}
+	}
+	return fmt.Sprintf("%s does not implement %s: missing %s", xtyp, typ, strings.Join(missing, ", "))
+}
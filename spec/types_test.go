@@ -0,0 +1,81 @@
+// Copyright 2011, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spec
+
+/*  Filename:    types_test.go
+ *  Description: For testing types.go
+ */
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type writer struct{}
+
+func (w *writer) Write(p []byte) (int, error) { return len(p), nil }
+
+func TestTypeMatchers(T *testing.T) {
+	var ptr *bytes.Buffer
+	var err error
+	describePassing(T, "Type matchers", func(s *SpecTest) {
+		s.It("match", func() {
+			s.Spec(nil, Should, BeNil)
+			s.Spec(ptr, Should, BeNil)
+			s.Spec(interface{}(ptr), Should, BeNil)
+			s.Spec(func() *bytes.Buffer { return nil }, Should, BeNil)
+			s.Spec(map[int]int(nil), Should, BeNil)
+			s.Spec([]int{}, Should, Not, BeNil)
+			s.Spec(0, Should, Not, BeNil)
+			s.Spec(struct{ A int }{}, Should, BeZero)
+			s.Spec("a", Should, Not, BeZero)
+			s.Spec(1 < 2, Should, BeTrue)
+			s.Spec(func() bool { return false }, Should, BeFalse)
+			s.Spec(int64(1), Should, BeAssignableTo, int64(0))
+			s.Spec(1, Should, Not, BeAssignableTo, int64(0))
+			s.Spec(nil, Should, BeAssignableTo, reflect.TypeOf(&err).Elem())
+			s.Spec(new(bytes.Buffer), Should, Implement, (*io.Writer)(nil))
+			s.Spec(writer{}, Should, Not, Implement, (*io.Writer)(nil))
+			s.Spec(nil, Should, Not, Implement, (*fmt.Stringer)(nil))
+		})
+	})
+}
+
+func TestTypeMatcherErrors(T *testing.T) {
+	for _, test := range []struct {
+		m    Matcher
+		args []interface{}
+	}{
+		{BeTrue, []interface{}{1}},
+		{BeFalse, []interface{}{nil}},
+		{BeAssignableTo, []interface{}{1, nil}},
+		{Implement, []interface{}{1, io.Writer(nil)}},
+		{Implement, []interface{}{1, new(int)}},
+	} {
+		if _, err := test.m.Matches(test.args); err == nil && test.m.Error() == nil {
+			T.Errorf("%s accepted %#v", test.m, test.args)
+		}
+	}
+}
+
+func TestTypeExplanations(T *testing.T) {
+	for _, test := range []struct {
+		m      Matcher
+		args   []interface{}
+		expect string
+	}{
+		{BeNil, []interface{}{[]int{}}, "[]int{} is not nil"},
+		{BeZero, []interface{}{1}, "1 is not the zero int"},
+		{BeAssignableTo, []interface{}{1, ""}, "int is not assignable to string"},
+		{Implement, []interface{}{writer{}, (*io.ReadWriter)(nil)}, "spec.writer does not implement io.ReadWriter: missing Read, Write (pointer receiver)"},
+	} {
+		if s := test.m.(explainer).explain(test.args); s != test.expect {
+			T.Errorf("%s: unexpected explanation %q", test.m, s)
+		}
+	}
+}